@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/binary"
 	"flag"
 	"fmt"
 	"log"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/jiwonac/go-fkc/fkc"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -22,19 +24,20 @@ Generate random points in the unit d-dimensional cube to construct random graph.
 Then, saves generated graph as CSV and MongoDB collection.
 */
 
-// A point with its group and its coordinates, and its neighbors' indices
+// A point with its groups and its coordinates, and its neighbors' indices
 type Point struct {
-	group int
-	coord []float64
+	groups fkc.GroupSet
+	coord  []float64
 }
 
-// A point's group and the indices of its neighbors
+// A point's groups and the indices of its neighbors
 // Essentially the same content that will be stored in MongoDB
 type PointNeighbors struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Index     int                `bson:"index"`
-	Group     int                `bson:"group"`
-	Neighbors []bool             `bson:"neighbors"`
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Index        int                `bson:"index"`
+	Group        fkc.GroupSet       `bson:"group"`
+	Neighbors    []int              `bson:"neighbors,omitempty"`
+	NeighborBits []byte             `bson:"neighborbits,omitempty"` // Little-endian uint64 words
 }
 
 func main() {
@@ -42,16 +45,28 @@ func main() {
 	n := flag.Int("n", 1000, "Number of points generated")
 	d := flag.Int("d", 3, "Number of dimensions of the hypercube")
 	m := flag.Int("m", 5, "Number of distinct groups")
+	maxGroups := flag.Int("maxgroups", 1, "Each point joins between 1 and this many distinct groups")
 	r := flag.Float64("r", 0.2, "Distance threshold for adjacency")
 	db := flag.String("db", "dummydb", "Name of MongoDB database")
+	seed := flag.Int64("seed", 1, "Seed of the random points & groups; the same seed generates the same graph")
+	bitset := flag.Bool("bitset", false, "Store neighbors as bitsets rather than index lists")
 	flag.Parse()
+	if *maxGroups < 1 || *maxGroups > *m {
+		log.Fatalf("maxgroups %d not in 1...%d", *maxGroups, *m)
+	}
 
 	graphID := getGraphID(*n, *d, *m, *r)
 	fmt.Println("graphID: ", graphID)
-	points := generatePoints(*n, *d, *m)
+	rng := rand.New(rand.NewSource(*seed))
+	points := generatePoints(*n, *d, *m, *maxGroups, rng)
 	adjList := adjacencyList(points, *r)
+	if *bitset {
+		packNeighbors(adjList, *n)
+	}
 	//printStats(adjList, *n, *m)
-	storeMongo(adjList, *db, graphID)
+	if err := storeMongo(adjList, *db, graphID); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // Returns a unique string identifier for the graph's parameters
@@ -60,18 +75,19 @@ func getGraphID(n int, d int, m int, r float64) string {
 	return str + "r" + strconv.Itoa(int(r*100))
 }
 
-func generatePoints(n int, d int, m int) []Point {
+func generatePoints(n int, d int, m int, maxGroups int, rng *rand.Rand) []Point {
 	points := make([]Point, n) // Slice of points
 	// Iterate for each point
 	for i := 0; i < n; i++ {
 		// Create point w/ random group assignment
+		numGroups := 1 + rng.Intn(maxGroups)
 		point := Point{
-			group: rand.Intn(m),
-			coord: make([]float64, d),
+			groups: fkc.NewGroupSet(rng.Perm(m)[:numGroups]...),
+			coord:  make([]float64, d),
 		}
 		// Generate random numbers into each coordinate
 		for j := 0; j < d; j++ {
-			point.coord[j] = rand.Float64()
+			point.coord[j] = rng.Float64()
 		}
 		points[i] = point // Add point to slice of points
 	}
@@ -85,25 +101,46 @@ func adjacencyList(points []Point, r float64) []PointNeighbors {
 		point := points[i]
 		adj := PointNeighbors{
 			Index:     i,
-			Group:     point.group,
-			Neighbors: make([]bool, n),
+			Group:     point.groups,
+			Neighbors: make([]int, 0),
 		}
 		for j := 0; j < n; j++ {
 			other := points[j]
-			adj.Neighbors[j] = dist(point.coord, other.coord) <= r
+			if dist(point.coord, other.coord) <= r {
+				adj.Neighbors = append(adj.Neighbors, j)
+			}
 		}
 		adjList[i] = adj
 	}
 	return adjList
 }
 
+// Replaces each neighbor list with a bitset of n bits, encoded as
+// little-endian uint64 words
+func packNeighbors(adjList []PointNeighbors, n int) {
+	numWords := (n + 63) / 64
+	for i := range adjList {
+		words := make([]uint64, numWords)
+		for _, j := range adjList[i].Neighbors {
+			words[j/64] |= 1 << uint(j%64)
+		}
+		data := make([]byte, 8*numWords)
+		for w, word := range words {
+			binary.LittleEndian.PutUint64(data[8*w:], word)
+		}
+		adjList[i].NeighborBits = data
+		adjList[i].Neighbors = nil
+	}
+}
+
 func printStats(adjList []PointNeighbors, n int, m int) {
 	groupCounts := make([]int, m)
 	neighborCounts := make([]int, n)
 	for i := range adjList {
 		adj := adjList[i]
-		group := adj.Group
-		groupCounts[group]++
+		for _, group := range adj.Group {
+			groupCounts[group]++
+		}
 		neighborCounts[i] = len(adj.Neighbors)
 	}
 	fmt.Printf("Group counts: %v\n", groupCounts)
@@ -113,16 +150,18 @@ func printStats(adjList []PointNeighbors, n int, m int) {
 	fmt.Printf("Median neighbors: %d\n", neighborCounts[n/2])
 }
 
-func storeMongo(adjList []PointNeighbors, db string, graphID string) {
+func storeMongo(adjList []PointNeighbors, db string, graphID string) error {
 	// Connect to MongoDB
 	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://localhost:27017"))
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("create client: %w", err)
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	err = client.Connect(ctx)
-	handleError(err)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
 	defer client.Disconnect(ctx)
 
 	// Open database
@@ -133,12 +172,16 @@ func storeMongo(adjList []PointNeighbors, db string, graphID string) {
 
 	// If the collection is already nonempty, then empty its contents
 	_, err = collection.DeleteMany(context.Background(), bson.M{})
-	handleError(err)
+	if err != nil {
+		return fmt.Errorf("empty collection %s: %w", graphID, err)
+	}
 
 	// Insert entries into collection
 	for i := range adjList {
 		_, err = collection.InsertOne(context.Background(), adjList[i])
-		handleError(err)
+		if err != nil {
+			return fmt.Errorf("insert point %d: %w", adjList[i].Index, err)
+		}
 	}
 
 	// Define the index model for the Index field
@@ -150,7 +193,10 @@ func storeMongo(adjList []PointNeighbors, db string, graphID string) {
 
 	// Create the index on the collection
 	_, err = collection.Indexes().CreateOne(context.Background(), indexModel)
-	handleError(err)
+	if err != nil {
+		return fmt.Errorf("create index: %w", err)
+	}
+	return nil
 }
 
 // Euclidean distance between two points
@@ -163,10 +209,3 @@ func dist(foo []float64, bar []float64) float64 {
 	}
 	return math.Sqrt(sumSquares)
 }
-
-// Very basic error logging
-func handleError(err error) {
-	if err != nil {
-		log.Fatal(err)
-	}
-}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/**
+Rewrites a collection created with the dense adjacency representation, where
+each point stores an n-length []bool of neighbors, into the sparse
+representation that stores the list of neighbor indices instead.
+Documents that are already sparse are left untouched, so the migration can be
+rerun safely after an interruption.
+*/
+
+type densePoint struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Neighbors bson.RawValue      `bson:"neighbors"`
+}
+
+func main() {
+	// Parse flags
+	db := flag.String("db", "dummydb", "Name of MongoDB database")
+	col := flag.String("col", "n1000d3m5r20", "Name of MongoDB collection to migrate")
+	batchSize := flag.Int("batch", 1000, "DB batch size")
+	flag.Parse()
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+	collection := client.Database(*db).Collection(*col)
+
+	migrated, err := migrateCollection(collection, *batchSize)
+	fmt.Printf("\nMigrated %d documents to sparse neighbors.\n", migrated)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func migrateCollection(collection *mongo.Collection, batchSize int) (int, error) {
+	projection := options.Find().SetProjection(bson.M{"neighbors": 1})
+	cur, err := collection.Find(context.Background(), bson.M{}, projection)
+	if err != nil {
+		return 0, fmt.Errorf("find points: %w", err)
+	}
+	defer cur.Close(context.Background())
+
+	migrated := 0
+	updates := make([]mongo.WriteModel, 0, batchSize)
+	for i := 0; cur.Next(context.Background()); i++ {
+		var point densePoint
+		if err := cur.Decode(&point); err != nil {
+			return migrated, fmt.Errorf("decode document %d: %w", i, err)
+		}
+		neighbors, dense, err := sparseNeighbors(point.Neighbors)
+		if err != nil {
+			return migrated, fmt.Errorf("read neighbors of %v: %w", point.ID, err)
+		}
+		if dense {
+			update := mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": point.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"neighbors": neighbors}})
+			updates = append(updates, update)
+		}
+		if len(updates) == batchSize {
+			count, err := flushUpdates(collection, updates)
+			migrated += count
+			if err != nil {
+				return migrated, err
+			}
+			updates = updates[:0]
+			fmt.Printf("\rScanned %d documents.", i+1)
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return migrated, fmt.Errorf("iterate cursor: %w", err)
+	}
+	count, err := flushUpdates(collection, updates)
+	return migrated + count, err
+}
+
+// Converts a dense []bool row into neighbor indices. The second return value
+// is false if the row is not in the dense representation.
+func sparseNeighbors(raw bson.RawValue) ([]int, bool, error) {
+	array, ok := raw.ArrayOK()
+	if !ok {
+		return nil, false, nil
+	}
+	values, err := array.Values()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	neighbors := make([]int, 0)
+	for i, value := range values {
+		adjacent, ok := value.BooleanOK()
+		if !ok { // Already sparse
+			return nil, false, nil
+		}
+		if adjacent {
+			neighbors = append(neighbors, i)
+		}
+	}
+	return neighbors, true, nil
+}
+
+func flushUpdates(collection *mongo.Collection, updates []mongo.WriteModel) (int, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	result, err := collection.BulkWrite(context.Background(), updates)
+	if err != nil {
+		return 0, fmt.Errorf("write batch: %w", err)
+	}
+	return int(result.ModifiedCount), nil
+}
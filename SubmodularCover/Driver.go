@@ -1,24 +1,64 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/jiwonac/go-fkc/fkc"
 )
 
+/**
+Command-line wrapper around the fkc package.
+*/
+
 func main() {
+	defaults := fkc.DefaultOptions()
 
 	// Define command-line flags
 	dbFlag := flag.String("db", "dummydb", "MongoDB DB")
 	collectionFlag := flag.String("col", "n1000d3m5r20", "ollection containing points")
 	coverageFlag := flag.Int("k", 20, "k-coverage requirement")
-	groupReqFlag := flag.Int("g", 100, "group count requirement")
+	coverageFileFlag := flag.String("kfile", "", "JSON or CSV file mapping point index to its own coverage requirement, overriding -k")
+	groupReqFlag := flag.String("g", "100", "group count requirement, either one value for all groups or a comma-separated list")
 	groupCntFlag := flag.Int("m", 5, "number of groups")
+	groupFileFlag := flag.String("gfile", "", "JSON or CSV file mapping group id to required count, overriding -g")
+	groupCapFlag := flag.String("gcap", "", "group caps, either one value for all groups or a comma-separated list; negative or empty means uncapped")
+	groupShareFlag := flag.Float64("gshare", 0, "each group requires at least this fraction of its size")
+	nodeWeightFileFlag := flag.String("wfile", "", "JSON or CSV file mapping point index to the weight of its residual coverage")
+	groupWeightFlag := flag.String("gw", "", "group weights, either one value for all groups or a comma-separated list; empty weighs every group 1")
+	groupWeightFileFlag := flag.String("gwfile", "", "JSON or CSV file mapping group id to its weight, overriding -gw")
+	costFileFlag := flag.String("costfile", "", "JSON or CSV file mapping point index to its selection cost, overriding the cost field")
+	costAware := flag.Bool("costaware", false, "pick the best marginal gain per unit of cost")
+	target := flag.Float64("target", defaults.Target, "fraction of the total requirement to satisfy before stopping")
+	budget := flag.Int("budget", defaults.Budget, "select at most this many points, maximizing the requirement met; negative for no budget")
+	initFileFlag := flag.String("initfile", "", "file listing the indices of an existing coreset to extend, e.g. a printed coreset")
+	prevFileFlag := flag.String("prev", "", "result saved with -save to extend to points added to the store since")
+	saveFileFlag := flag.String("save", "", "file to save the result to, for a later -prev run")
+	prune := flag.Bool("prune", false, "remove coreset points made redundant by later selections")
 	optimFlag := flag.Int("optim", 0, "optimization mode")
-	threadsFlag := flag.Int("t", 1, "number of threads")
-	dense := flag.Bool("dense", true, "whether the graph is denser than the k-Coverage requirement")
-	eps := flag.Float64("eps", 0.1, "portion of dataset randomly sampled in each iteration of LazyLazy")
-	objRatio := flag.Float64("objratio", 0.9, "portion of objective function to be satisfied with LazyLazy before switching to Lazy")
+	tieBreakFlag := flag.String("tiebreak", fkc.LowestIndex.String(), "how equal gains are decided: index, degree, random or group")
+	threadsFlag := flag.Int("t", defaults.Threads, "number of threads")
+	dense := flag.Bool("dense", defaults.Dense, "whether the graph is denser than the k-Coverage requirement")
+	eps := flag.Float64("eps", defaults.Eps, "portion of dataset randomly sampled in each iteration of LazyLazy")
+	objRatio := flag.Float64("objratio", defaults.ObjRatio, "portion of objective function to be satisfied with LazyLazy before switching to Lazy")
+	clip := flag.Bool("clip", false, "lower requirements the data cannot meet instead of failing; implied by -budget")
+	alpha := flag.Float64("alpha", defaults.Alpha, "gain fraction below which DisCover doubles its cardinality constraint")
+	storeFlag := flag.String("store", "mongo", "where points are served from: mongo or memory")
+	adjFileFlag := flag.String("adjfile", "", "adjacency list file to load into memory instead of MongoDB")
+	groupAssignFlag := flag.String("groupfile", "", "group assignment file accompanying -adjfile")
+	bitset := flag.Bool("bitset", false, "whether the memory store packs neighbors into bitsets")
+	seed := flag.Int64("seed", defaults.Seed, "seed of the random sampling; the same seed & thread count give the same coreset")
+	timeout := flag.Duration("timeout", 0, "stop and report the coreset so far after this long, e.g. 30m; 0 for no limit")
+	checkpoint := flag.String("checkpoint", "", "file to periodically save the solver state to")
+	checkpointEvery := flag.Int("checkpointevery", defaults.CheckpointEvery, "number of selections between two checkpoints")
+	resume := flag.Bool("resume", false, "continue from the state saved in the -checkpoint file")
 	iterPrint := flag.Bool("iterprint", true, "whether to report each iteration's progress")
 	//batchSize := flag.Int("batch", 10000, "number of entries to query from MongoDB at once")
 
@@ -26,18 +66,297 @@ func main() {
 	flag.Parse()
 
 	// Make the groupReqs array
-	groupReqs := make([]int, *groupCntFlag)
-	for i := 0; i < *groupCntFlag; i++ {
-		groupReqs[i] = *groupReqFlag
+	groupReqs, err := getGroupReqs(*groupReqFlag, *groupFileFlag, *groupCntFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read per-point coverage requirements, if any
+	var coverageReqs map[int]int
+	if *coverageFileFlag != "" {
+		coverageReqs, err = fkc.ReadCoverageReqs(*coverageFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Caps are given like requirements
+	groupCaps, err := getGroupReqs(*groupCapFlag, "", *groupCntFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read objective weights, if any
+	var nodeWeights map[int]float64
+	if *nodeWeightFileFlag != "" {
+		nodeWeights, err = fkc.ReadNodeWeights(*nodeWeightFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	groupWeights, err := getGroupWeights(*groupWeightFlag, *groupWeightFileFlag, *groupCntFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read selection costs, if any
+	var costs map[int]float64
+	if *costFileFlag != "" {
+		costs, err = fkc.ReadCosts(*costFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Read the coreset to extend, if any
+	var initial []int
+	if *initFileFlag != "" {
+		initial, err = fkc.ReadIndices(*initFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Read the result to extend, if any
+	var previous *fkc.Result
+	if *prevFileFlag != "" {
+		previous, err = fkc.LoadResult(*prevFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	tieBreak, err := fkc.ParseTieBreak(*tieBreakFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read the state to continue from, if resuming
+	var resumeFrom *fkc.Checkpoint
+	if *resume {
+		if *checkpoint == "" {
+			log.Fatal("-resume requires -checkpoint")
+		}
+		resumeFrom, err = fkc.LoadCheckpoint(*checkpoint)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Collect solver options
+	opts := defaults
+	opts.CoverageReq = *coverageFlag
+	opts.CoverageReqs = coverageReqs
+	opts.GroupReqs = groupReqs
+	opts.GroupShare = *groupShareFlag
+	opts.GroupCaps = groupCaps
+	opts.NodeWeights = nodeWeights
+	opts.GroupWeights = groupWeights
+	opts.Costs = costs
+	opts.CostAware = *costAware
+	opts.Target = *target
+	opts.Budget = *budget
+	opts.Initial = initial
+	opts.Previous = previous
+	opts.Prune = *prune
+	opts.Algorithm = fkc.Algorithm(*optimFlag)
+	opts.TieBreak = tieBreak
+	opts.Threads = *threadsFlag
+	opts.Dense = *dense
+	opts.Clip = *clip
+	opts.Eps = *eps
+	opts.ObjRatio = *objRatio
+	opts.Alpha = *alpha
+	opts.Seed = *seed
+	opts.Print = *iterPrint
+	opts.Checkpoint = *checkpoint
+	opts.CheckpointEvery = *checkpointEvery
+	opts.Resume = resumeFrom
+	if err := opts.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Get the points from DB or file
+	store, err := getStore(*storeFlag, *dbFlag, *collectionFlag, *adjFileFlag, *groupAssignFlag, *bitset)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Stop early on Ctrl-C or once the timeout passes
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
 	}
+	go func() { // Once stopping, a second Ctrl-C kills the process as usual
+		<-ctx.Done()
+		stop()
+	}()
 
 	// Run submodularCover
 	start := time.Now()
-	result := SubmodularCover(*dbFlag, *collectionFlag, *coverageFlag, groupReqs, *optimFlag, *threadsFlag, *dense, *eps, *objRatio, *iterPrint)
+	result, err := fkc.SolveContext(ctx, store, opts)
 	elapsed := time.Since(start)
+	if result == nil { // Nothing was selected
+		log.Fatal(err)
+	} else if err != nil { // Still report whatever was selected before the failure
+		fmt.Printf("\nSolver stopped early: %v\n", err)
+	}
 
 	// Report resultant coreset & time taken
-	fmt.Printf("%v\n", result)
-	fmt.Print("Obtained solution of size ", len(result), " in ")
+	printReport(result, elapsed)
+	if *saveFileFlag != "" {
+		if err := fkc.SaveResult(*saveFileFlag, result); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func printReport(result *fkc.Result, elapsed time.Duration) {
+	fmt.Printf("%v\n", result.Coreset)
+	fmt.Print("Obtained solution of size ", len(result.Coreset), " in ")
 	fmt.Printf("%s\n", elapsed)
+	fmt.Printf("Marginal gains: %v\n", result.Gains)
+	if result.Pruned > 0 {
+		fmt.Printf("Pruned %d redundant points\n", result.Pruned)
+	}
+	fmt.Printf("Total cost: %v\n", result.TotalCost)
+	fmt.Printf("Objective value: %v\n", result.Objective)
+	remainingCoverage, remainingGroups := 0, 0
+	for _, residual := range result.CoverageTracker {
+		remainingCoverage += residual
+	}
+	for _, residual := range result.GroupTracker {
+		remainingGroups += residual
+	}
+	fmt.Printf("Requirements satisfied: %v (remaining coverage %d, remaining group %d, objective fraction %.4f)\n",
+		result.Satisfied, remainingCoverage, remainingGroups, result.Fraction)
+	if f := result.Feasibility; f != nil && !f.Feasible() {
+		fmt.Printf("Clipped requirements of groups %v, of capped groups %v, of %d nodes and of %d nodes limited by caps\n",
+			f.InfeasibleGroups, f.CappedGroups, len(f.InfeasibleNodes), len(f.CappedNodes))
+	}
+	if result.CapTracker != nil {
+		saturated := make([]int, 0)
+		for group, capacity := range result.CapTracker {
+			if capacity == 0 {
+				saturated = append(saturated, group)
+			}
+		}
+		fmt.Printf("Groups at their cap: %v\n", saturated)
+	}
+	fmt.Printf("Marginal gain evaluations: %d, store queries: %d\n", result.GainEvals, result.Queries)
+	for _, phase := range result.Phases {
+		fmt.Printf("  %s: %s\n", phase.Name, phase.Duration)
+	}
+}
+
+// Group requirements come from the file if one is given, and otherwise from
+// the -g value, which is either repeated across all m groups or a full list
+func getGroupReqs(value string, fileName string, m int) ([]int, error) {
+	if fileName != "" {
+		groupReqs, err := fkc.ReadGroupReqs(fileName)
+		if err != nil {
+			return nil, err
+		}
+		for len(groupReqs) < m { // Groups missing from the file require nothing
+			groupReqs = append(groupReqs, 0)
+		}
+		return groupReqs, nil
+	}
+	if strings.TrimSpace(value) == "" { // No group requirements
+		return []int{}, nil
+	}
+	parts := strings.Split(value, ",")
+	groupReqs := make([]int, len(parts))
+	for i, part := range parts {
+		req, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid group list %q: %w", value, err)
+		}
+		groupReqs[i] = req
+	}
+	if len(groupReqs) == 1 {
+		req := groupReqs[0]
+		groupReqs = make([]int, m)
+		for i := 0; i < m; i++ {
+			groupReqs[i] = req
+		}
+	}
+	return groupReqs, nil
+}
+
+// Group weights mirror getGroupReqs, except that groups missing from a file
+// weigh 1
+func getGroupWeights(value string, fileName string, m int) ([]float64, error) {
+	if fileName != "" {
+		groupWeights, err := fkc.ReadGroupWeights(fileName)
+		if err != nil {
+			return nil, err
+		}
+		for len(groupWeights) < m {
+			groupWeights = append(groupWeights, 1)
+		}
+		return groupWeights, nil
+	}
+	if strings.TrimSpace(value) == "" { // Unweighted
+		return []float64{}, nil
+	}
+	parts := strings.Split(value, ",")
+	groupWeights := make([]float64, len(parts))
+	for i, part := range parts {
+		weight, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -gw value %q: %w", value, err)
+		}
+		groupWeights[i] = weight
+	}
+	if len(groupWeights) == 1 {
+		weight := groupWeights[0]
+		groupWeights = make([]float64, m)
+		for i := 0; i < m; i++ {
+			groupWeights[i] = weight
+		}
+	}
+	return groupWeights, nil
+}
+
+func getStore(storeType string, dbName string, collectionName string,
+	adjFileName string, groupFileName string, bitset bool) (fkc.PointStore, error) {
+	switch storeType {
+	case "mongo":
+		store, err := fkc.NewMongoStore(dbName, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("obtained collection\n")
+		return store, nil
+	case "memory":
+		var store *fkc.MemoryStore
+		var err error
+		if adjFileName != "" {
+			store, err = fkc.LoadMemoryStoreFromFiles(adjFileName, groupFileName)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Printf("loaded points from %s into memory\n", adjFileName)
+		} else {
+			source, err := fkc.NewMongoStore(dbName, collectionName)
+			if err != nil {
+				return nil, err
+			}
+			store, err = fkc.LoadMemoryStore(source)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Printf("loaded collection into memory\n")
+		}
+		if bitset {
+			store.PackNeighbors()
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown store %q, expected mongo or memory", storeType)
+	}
 }
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/jiwonac/go-fkc/fkc"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -23,14 +24,16 @@ The text file has format:
 index : { neighbor, neighbor, ..., neighbor}
 Group assignment is given by another text file, of format:
 index : group
+or, for a point that belongs to several groups,
+index : group, group, ..., group
 The last line of the text files should be an empty line to avoid EOF errors.
 */
 
 type Point struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	Index     int                `bson:"index"`
-	Group     int                `bson:"group"`
-	Neighbors []bool             `bson:"neighbors"`
+	Group     fkc.GroupSet       `bson:"group"`
+	Neighbors []int              `bson:"neighbors"`
 }
 
 func main() {
@@ -40,54 +43,69 @@ func main() {
 	adjFileName := flag.String("adjfile", "test1.txt", "File containing adjacency lists")
 	groupFileName := flag.String("groupfile", "test2.txt", "File containing group assignments")
 	batchSize := flag.Int("batch", 1000, "DB batch size")
-	n := flag.Int("n", 50000, "total number of points in dataset")
 	flag.Parse()
 
 	// Access DB & files
-	collection, client := getMongoCollection(*db, *col)
-	adjFileScanner, adjFile := getFileScanner(*adjFileName)
-	groupFileScanner, groupFile := getFileScanner(*groupFileName)
+	collection, client, err := getMongoCollection(*db, *col)
+	if err != nil {
+		log.Fatal(err)
+	}
 	defer client.Disconnect(context.Background())
+	adjFileScanner, adjFile, err := getFileScanner(*adjFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
 	defer adjFile.Close()
+	groupFileScanner, groupFile, err := getFileScanner(*groupFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
 	defer groupFile.Close()
 
-	insertIntoCollection(collection, adjFileScanner, groupFileScanner, *batchSize, *n)
-	createIndex(collection)
+	err = insertIntoCollection(collection, adjFileScanner, groupFileScanner, *batchSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = createIndex(collection); err != nil {
+		log.Fatal(err)
+	}
 }
 
-func getMongoCollection(db string, col string) (*mongo.Collection, *mongo.Client) {
+func getMongoCollection(db string, col string) (*mongo.Collection, *mongo.Client, error) {
 	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
-	handleError(err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect: %w", err)
+	}
 
 	database := client.Database(db)
 	collection := database.Collection(col)
 
 	_, err = collection.DeleteMany(context.Background(), bson.M{})
-	handleError(err)
-	return collection, client
+	if err != nil {
+		return nil, nil, fmt.Errorf("empty collection %s: %w", col, err)
+	}
+	return collection, client, nil
 }
 
-func getFileScanner(fileName string) (*bufio.Reader, *os.File) {
+func getFileScanner(fileName string) (*bufio.Reader, *os.File, error) {
 	file, err := os.Open(fileName)
-	handleError(err)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	reader := bufio.NewReader(file)
 
 	//scanner := bufio.NewScanner(file)
-	return reader, file
+	return reader, file, nil
 }
 
-func handleError(err error) {
-	if err != nil {
-		log.Fatal(err)
-	}
-}
-
-func parseAdjLine(scanner *bufio.Reader, n int) []bool {
+func parseAdjLine(scanner *bufio.Reader) ([]int, error) {
 	ints := make([]int, 0)
 	for {
 		line, err := scanner.ReadString('\n')
-		handleError(err)
+		if err != nil {
+			return nil, err
+		}
 		split := strings.Split(line, " : ")
 		if len(split) > 1 { // Index on left side
 			line = split[1]
@@ -100,6 +118,9 @@ func parseAdjLine(scanner *bufio.Reader, n int) []bool {
 		line = strings.Trim(line, "{ }\n")
 		split = strings.Split(line, ", ")
 		for _, v := range split {
+			if v == "" { // Empty adjacency list
+				continue
+			}
 			n, _ := strconv.Atoi(v)
 			ints = append(ints, n)
 		}
@@ -107,40 +128,43 @@ func parseAdjLine(scanner *bufio.Reader, n int) []bool {
 			break
 		}
 	}
-	matrix := make([]bool, n)
-	for i := 0; i < n; i++ {
-		matrix[i] = false
-	}
-	for i := 0; i < len(ints); i++ {
-		matrix[ints[i]] = true
-	}
-	return matrix
+	return ints, nil
 }
 
-func parseGroupLine(scanner *bufio.Reader) int {
+func parseGroupLine(scanner *bufio.Reader) (fkc.GroupSet, error) {
 	line, err := scanner.ReadString('\n')
-	handleError(err)
+	if err != nil {
+		return nil, err
+	}
 	line = strings.Trim(line, "\n")
 	parts := strings.Split(line, " : ")
-	i, err := strconv.Atoi(parts[1])
-	handleError(err)
-	return i
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("malformed group line %q", line)
+	}
+	return fkc.ParseGroupSet(parts[1])
 }
 
 func insertIntoCollection(collection *mongo.Collection,
-	adjFileScanner *bufio.Reader, groupFileScanner *bufio.Reader, batchSize int, n int) {
+	adjFileScanner *bufio.Reader, groupFileScanner *bufio.Reader, batchSize int) error {
 	// Iterate over line of files
 	for i := 0; true; i++ {
 		if !hasNext(adjFileScanner, groupFileScanner) {
-			return
+			return nil
 		}
 
 		points := make([]interface{}, batchSize)
 		for j := 0; j < batchSize; j++ {
-			adjList := parseAdjLine(adjFileScanner, n)
-			group := parseGroupLine(groupFileScanner)
+			index := batchSize*i + j
+			adjList, err := parseAdjLine(adjFileScanner)
+			if err != nil {
+				return fmt.Errorf("parse adjacency of point %d: %w", index, err)
+			}
+			group, err := parseGroupLine(groupFileScanner)
+			if err != nil {
+				return fmt.Errorf("parse group of point %d: %w", index, err)
+			}
 			point := Point{
-				Index:     batchSize*i + j,
+				Index:     index,
 				Group:     group,
 				Neighbors: adjList,
 			}
@@ -148,22 +172,28 @@ func insertIntoCollection(collection *mongo.Collection,
 
 			if j == batchSize-1 || !hasNext(adjFileScanner, groupFileScanner) {
 				_, err := collection.InsertMany(context.Background(), points[:j+1])
-				handleError(err)
+				if err != nil {
+					return fmt.Errorf("insert batch %d: %w", i, err)
+				}
 				fmt.Printf("\rBatch %d complete.", i)
 			}
 		}
 		fmt.Printf("\n")
 	}
+	return nil
 }
 
-func createIndex(collection *mongo.Collection) {
+func createIndex(collection *mongo.Collection) error {
 	indexModel := mongo.IndexModel{
 		Keys: bson.M{
 			"index": 1,
 		},
 	}
 	_, err := collection.Indexes().CreateOne(context.Background(), indexModel)
-	handleError(err)
+	if err != nil {
+		return fmt.Errorf("create index: %w", err)
+	}
+	return nil
 }
 
 func hasNext(adjFileScanner *bufio.Reader, groupFileScanner *bufio.Reader) bool {
@@ -0,0 +1,355 @@
+package fkc
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+/**
+Checkpoints of a running solve. The top-level algorithm periodically writes
+its state to a file, and a later Solve with Options.Resume continues from that
+state, selecting the same coreset as an uninterrupted run would.
+*/
+
+// Snapshot of the solver state, taken between two selections
+type Checkpoint struct {
+	Algorithm       Algorithm
+	Seed            int64
+	Stage           int          // Stage of MultiLevel: 0 for lazylazy, 1 for lazy greedy
+	StageStart      int          // Length of the coreset chosen by earlier stages
+	Coreset         []int        // Every point selected so far after Options.Initial, in selection order
+	Gains           []float64    // Marginal gain of each coreset point when it was selected
+	CoverageTracker []int        // Residual coverage requirement of each point
+	GroupTracker    []int        // Residual requirement of each group
+	CapTracker      []int        // Remaining capacity of each group, or nil without caps
+	Candidates      []int        // Remaining candidates, except under lazy greedy
+	Bounds          []QueueEntry // Lazy greedy's priority queue of remaining candidates
+	Constraint      int          // Cardinality constraint of lazylazy
+	SampleSize      int          // Candidates sampled in each iteration of lazylazy
+	ObjScore        float64      // Remaining score at which lazylazy stops
+	Cardinality     int          // Cardinality constraint of DisCover's next round
+	Round           int          // Iterations or rounds completed, for progress reports
+	RandomDraws     int64        // Values drawn from the run's random source
+	GainEvals       int64        // Marginal gain evaluations so far
+	Queries         int64        // Point store queries so far
+
+	// Options that shape the requirements, which a resumed run must repeat
+	Initial      []int // Options.Initial, which includes a previous result's coreset
+	CoverageReq  int
+	CoverageReqs map[int]int
+	GroupReqs    []int
+	GroupShare   float64
+	GroupCaps    []int
+	Target       float64
+	Budget       int
+	Clip         bool
+
+	// Options that steer the selection, which a resumed run must repeat too
+	NodeWeights  map[int]float64
+	GroupWeights []float64
+	CostAware    bool
+	Costs        map[int]float64
+	TieBreak     TieBreak
+	Threads      int
+	Dense        bool
+	Eps          float64
+	ObjRatio     float64
+	Alpha        float64
+}
+
+// An upper bound on a candidate's priority, as kept in lazy greedy's queue
+type QueueEntry struct {
+	Index    int
+	Priority float64
+	TieKey   float64
+}
+
+// Reads a checkpoint written by Solve
+func LoadCheckpoint(fileName string) (*Checkpoint, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	checkpoint := &Checkpoint{}
+	if err := gob.NewDecoder(file).Decode(checkpoint); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", fileName, err)
+	}
+	return checkpoint, nil
+}
+
+// Writes the checkpoint to a temporary file first, so that a crash midway
+// leaves the previous checkpoint intact
+func (c *Checkpoint) save(fileName string) error {
+	file, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // No-op after the rename
+	if err := gob.NewEncoder(file).Encode(c); err != nil {
+		file.Close()
+		return fmt.Errorf("checkpoint %s: %w", fileName, err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), fileName)
+}
+
+// The points selected by the checkpointed stage, copied so that the algorithm
+// can append to them
+func (c *Checkpoint) stageCoreset() []int {
+	return append(make([]int, 0, len(c.Coreset)), c.Coreset[c.StageStart:]...)
+}
+
+// The marginal gains of stageCoreset's points
+func (c *Checkpoint) stageGains() []float64 {
+	return append(make([]float64, 0, len(c.Gains)), c.Gains[c.StageStart:]...)
+}
+
+// Checks that the checkpoint was taken by a run of the same algorithm, with the
+// same initial points, requirements & selection options, on data of the same
+// shape
+func (c *Checkpoint) check(opts Options, coverageTracker []int, groupTracker []int, capTracker []int) error {
+	switch {
+	case c.Algorithm != opts.Algorithm:
+		return fmt.Errorf("checkpoint: taken by %v, not %v", c.Algorithm, opts.Algorithm)
+	case c.Seed != opts.Seed:
+		return fmt.Errorf("checkpoint: taken with seed %d, not %d", c.Seed, opts.Seed)
+	case !equalSlices(c.Initial, opts.Initial):
+		return fmt.Errorf("checkpoint: taken with different initial points")
+	case c.CoverageReq != opts.CoverageReq:
+		return fmt.Errorf("checkpoint: taken with coverage requirement %d, not %d", c.CoverageReq, opts.CoverageReq)
+	case !equalMaps(c.CoverageReqs, opts.CoverageReqs):
+		return fmt.Errorf("checkpoint: taken with different per-point coverage requirements")
+	case !equalSlices(c.GroupReqs, opts.GroupReqs):
+		return fmt.Errorf("checkpoint: taken with group requirements %v, not %v", c.GroupReqs, opts.GroupReqs)
+	case c.GroupShare != opts.GroupShare:
+		return fmt.Errorf("checkpoint: taken with group share %v, not %v", c.GroupShare, opts.GroupShare)
+	case !equalSlices(c.GroupCaps, opts.GroupCaps):
+		return fmt.Errorf("checkpoint: taken with group caps %v, not %v", c.GroupCaps, opts.GroupCaps)
+	case c.Target != opts.Target:
+		return fmt.Errorf("checkpoint: taken with target %v, not %v", c.Target, opts.Target)
+	case c.Budget != opts.Budget:
+		return fmt.Errorf("checkpoint: taken with budget %d, not %d", c.Budget, opts.Budget)
+	case c.Clip != opts.Clip:
+		return fmt.Errorf("checkpoint: taken with clip %v, not %v", c.Clip, opts.Clip)
+	case !equalMaps(c.NodeWeights, opts.NodeWeights):
+		return fmt.Errorf("checkpoint: taken with different node weights")
+	case !equalSlices(c.GroupWeights, opts.GroupWeights):
+		return fmt.Errorf("checkpoint: taken with group weights %v, not %v", c.GroupWeights, opts.GroupWeights)
+	case c.CostAware != opts.CostAware:
+		return fmt.Errorf("checkpoint: taken with cost awareness %v, not %v", c.CostAware, opts.CostAware)
+	case !equalMaps(c.Costs, opts.Costs):
+		return fmt.Errorf("checkpoint: taken with different point costs")
+	case c.TieBreak != opts.TieBreak:
+		return fmt.Errorf("checkpoint: taken with tie-break %v, not %v", c.TieBreak, opts.TieBreak)
+	case c.Threads != opts.Threads:
+		return fmt.Errorf("checkpoint: taken with %d threads, not %d", c.Threads, opts.Threads)
+	case c.Dense != opts.Dense:
+		return fmt.Errorf("checkpoint: taken with dense %v, not %v", c.Dense, opts.Dense)
+	case c.Eps != opts.Eps || c.ObjRatio != opts.ObjRatio || c.Alpha != opts.Alpha:
+		return fmt.Errorf("checkpoint: taken with eps %v, objratio %v & alpha %v, not %v, %v & %v",
+			c.Eps, c.ObjRatio, c.Alpha, opts.Eps, opts.ObjRatio, opts.Alpha)
+	case len(c.CoverageTracker) != len(coverageTracker):
+		return fmt.Errorf("checkpoint: has %d points, store has %d", len(c.CoverageTracker), len(coverageTracker))
+	case len(c.GroupTracker) != len(groupTracker):
+		return fmt.Errorf("checkpoint: has %d groups, data has %d", len(c.GroupTracker), len(groupTracker))
+	case len(c.CapTracker) != len(capTracker):
+		return fmt.Errorf("checkpoint: has %d group caps, options have %d", len(c.CapTracker), len(capTracker))
+	case c.Stage < 0 || c.Stage > 1 || (c.Stage > 0 && opts.Algorithm != MultiLevel):
+		return fmt.Errorf("checkpoint: invalid stage %d", c.Stage)
+	case len(c.Gains) != len(c.Coreset):
+		return fmt.Errorf("checkpoint: has %d gains for %d coreset points", len(c.Gains), len(c.Coreset))
+	case c.StageStart < 0 || c.StageStart > len(c.Coreset):
+		return fmt.Errorf("checkpoint: stage start %d out of range", c.StageStart)
+	}
+	for _, index := range c.Coreset {
+		if index < 0 || index >= len(coverageTracker) {
+			return fmt.Errorf("checkpoint: coreset point %d out of range", index)
+		}
+	}
+	return nil
+}
+
+/**
+Checkpointing as seen by the algorithms. Only the top-level algorithm gets a
+checkpointer; the lazy greedy calls nested inside DisCover get nil, whose
+methods do nothing.
+*/
+
+type checkpointer struct {
+	run      *solverRun
+	opts     Options     // Options of the run, recorded in every checkpoint
+	fileName string      // Where checkpoints are written, or empty to only resume
+	every    int         // Selections between two checkpoints
+	saved    int         // Coreset size at the last checkpoint
+	stage    int         // Stage of MultiLevel being run
+	prefix   []int       // Coreset chosen by earlier stages
+	gains    []float64   // Gains of the prefix points
+	resume   *Checkpoint // State to continue from, until an algorithm takes it
+}
+
+func newCheckpointer(run *solverRun, opts Options) *checkpointer {
+	if opts.Checkpoint == "" && opts.Resume == nil {
+		return nil
+	}
+	cp := &checkpointer{
+		run:      run,
+		opts:     opts,
+		fileName: opts.Checkpoint,
+		every:    opts.CheckpointEvery,
+		resume:   opts.Resume,
+	}
+	if opts.Resume != nil {
+		cp.saved = len(opts.Resume.Coreset)
+	}
+	return cp
+}
+
+// Hands the state to continue from to the algorithm of the current stage, or
+// nil if it starts afresh
+func (cp *checkpointer) take() *Checkpoint {
+	if cp == nil || cp.resume == nil || cp.resume.Stage != cp.stage {
+		return nil
+	}
+	resume := cp.resume
+	cp.resume = nil
+	return resume
+}
+
+// Moves on to the next stage of MultiLevel, after the given coreset
+func (cp *checkpointer) nextStage(prefix []int, gains []float64) {
+	if cp == nil {
+		return
+	}
+	cp.stage++
+	cp.prefix, cp.gains = prefix, gains
+}
+
+// Writes a checkpoint once enough selections were made since the last one, or
+// once the run is cancelled, so that it can be resumed. Algorithms call it
+// between iterations. fill records the algorithm's own state; the trackers are
+// copied as they are.
+func (cp *checkpointer) save(coreset []int, gains []float64, coverageTracker []int, groupTracker []int, capTracker []int,
+	fill func(*Checkpoint)) error {
+	if cp == nil || cp.fileName == "" {
+		return nil
+	}
+	total := len(cp.prefix) + len(coreset)
+	if total-cp.saved < cp.every && cp.run.ctx.Err() == nil {
+		return nil
+	}
+	checkpoint := &Checkpoint{
+		Algorithm:       cp.opts.Algorithm,
+		Seed:            cp.opts.Seed,
+		Initial:         cp.opts.Initial,
+		CoverageReq:     cp.opts.CoverageReq,
+		CoverageReqs:    cp.opts.CoverageReqs,
+		GroupReqs:       cp.opts.GroupReqs,
+		GroupShare:      cp.opts.GroupShare,
+		GroupCaps:       cp.opts.GroupCaps,
+		Target:          cp.opts.Target,
+		Budget:          cp.opts.Budget,
+		Clip:            cp.opts.Clip,
+		NodeWeights:     cp.opts.NodeWeights,
+		GroupWeights:    cp.opts.GroupWeights,
+		CostAware:       cp.opts.CostAware,
+		Costs:           cp.opts.Costs,
+		TieBreak:        cp.opts.TieBreak,
+		Threads:         cp.opts.Threads,
+		Dense:           cp.opts.Dense,
+		Eps:             cp.opts.Eps,
+		ObjRatio:        cp.opts.ObjRatio,
+		Alpha:           cp.opts.Alpha,
+		Stage:           cp.stage,
+		StageStart:      len(cp.prefix),
+		Coreset:         append(append(make([]int, 0, total), cp.prefix...), coreset...),
+		Gains:           append(append(make([]float64, 0, total), cp.gains...), gains...),
+		CoverageTracker: coverageTracker,
+		GroupTracker:    groupTracker,
+		CapTracker:      capTracker,
+		RandomDraws:     cp.run.source.draws,
+		GainEvals:       cp.run.gainEvals,
+		Queries:         cp.run.queries,
+	}
+	fill(checkpoint)
+	if err := checkpoint.save(cp.fileName); err != nil {
+		return err
+	}
+	cp.saved = total
+	return nil
+}
+
+// Called when an iteration fails with err. If the run was cancelled, saves the
+// state from before the iteration, which the algorithm must have left intact,
+// and returns err either way.
+func (cp *checkpointer) saveOnCancel(err error, coreset []int, gains []float64, coverageTracker []int,
+	groupTracker []int, capTracker []int, fill func(*Checkpoint)) error {
+	if cp == nil || cp.run.ctx.Err() == nil {
+		return err
+	}
+	if saveErr := cp.save(coreset, gains, coverageTracker, groupTracker, capTracker, fill); saveErr != nil {
+		return fmt.Errorf("%w; saving checkpoint: %v", err, saveErr)
+	}
+	return err
+}
+
+// The queue's entries, in no particular order
+func (pq priorityQueue) entries() []QueueEntry {
+	entries := make([]QueueEntry, len(pq))
+	for i, item := range pq {
+		entries[i] = QueueEntry{Index: item.value, Priority: item.priority, TieKey: item.tieKey}
+	}
+	return entries
+}
+
+// Rebuilds a priority queue from its entries
+func queueFromEntries(entries []QueueEntry) priorityQueue {
+	pq := make(priorityQueue, len(entries))
+	for i, entry := range entries {
+		pq[i] = &pqItem{value: entry.Index, priority: entry.Priority, tieKey: entry.TieKey}
+	}
+	heap.Init(&pq)
+	return pq
+}
+
+/**
+Random source that counts its draws, so that a resumed run can fast-forward
+to where the checkpointed run left off.
+*/
+
+type countingSource struct {
+	source rand.Source64
+	draws  int64
+}
+
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{source: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.source.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.source.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.draws = 0
+	s.source.Seed(seed)
+}
+
+// Draws until the given number of values were drawn in total
+func (s *countingSource) skip(draws int64) {
+	for s.draws < draws {
+		s.Uint64()
+	}
+}
@@ -0,0 +1,126 @@
+package fkc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+/**
+Runs the classic greedy algorithm for submodular cover on the given candidates
+pool until all trackers are zeroed out.
+*/
+
+func classicGreedy(run *solverRun, store PointStore, coverageTracker []int,
+	groupTracker []int, capTracker []int, candidates map[int]bool, constraint int, threads int,
+	print bool, cp *checkpointer) ([]int, []float64, error) {
+	report("Executing classic greedy algorithm...\n", print)
+
+	// Initialize sets
+	n, err := store.Size()
+	if err != nil {
+		return nil, nil, err
+	}
+	coreset := make([]int, 0)
+	gains := make([]float64, 0)
+	if resume := cp.take(); resume != nil { // Continue where the checkpoint left off
+		coreset, gains = resume.stageCoreset(), resume.stageGains()
+		candidates = sliceToSet(resume.Candidates)
+	}
+	chunkSize := n / threads
+	state := func(c *Checkpoint) {
+		c.Candidates = mapToSlice(candidates)
+	}
+
+	// Repeat main loop until all requirements are met or candidate pool
+	// is dried out, or cardinality constraint is met
+	report("Entering the main loop...\n", print)
+	for !run.targetMet(coverageTracker, groupTracker) && len(candidates) > 0 && (constraint < 0 || len(coreset) < constraint) {
+		if err := cp.save(coreset, gains, coverageTracker, groupTracker, capTracker, state); err != nil {
+			return coreset, gains, err
+		}
+		if err := run.ctx.Err(); err != nil { // Cancelled or timed out
+			return coreset, gains, err
+		}
+		// Make the index range each worker scans
+		ranges := make([]indexRange, threads)
+		for t := 0; t < threads; t++ {
+			lo := t * chunkSize
+			hi := lo + chunkSize - 1
+			if t == threads-1 { // The last worker also takes the remainder
+				hi = n - 1
+			}
+			ranges[t] = indexRange{lo: lo, hi: hi}
+		}
+		// Actual work of concurrent candidate evaluation
+		results, err := parallelMap(run.ctx, threads, ranges,
+			func(ctx context.Context, r indexRange) (*gainResult, error) {
+				return classicWorker(ctx, run, store, candidates, coverageTracker, groupTracker, capTracker, r.lo, r.hi)
+			})
+		if err != nil {
+			return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+		}
+
+		// End-of-iteration bookkeeping
+		chosen := getBestResult(results)
+		if chosen.index < 0 { // Every remaining candidate is in a saturated group
+			break
+		}
+		point, err := store.GetPoint(chosen.index)
+		if err != nil {
+			return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+		}
+		coreset = append(coreset, chosen.index)
+		gains = append(gains, chosen.gain)
+		delete(candidates, chosen.index)
+		decrementTrackers(&point, coverageTracker, groupTracker, capTracker)
+		report("\rIteration: "+strconv.Itoa(len(coreset))+" complete with marginal gain "+fmt.Sprint(chosen.gain), print)
+		if chosen.gain == 0 {
+			report(fmt.Sprintf("%v %v\n", coverageTracker, groupTracker), print)
+		}
+	}
+	report("\n", print)
+	return coreset, gains, nil
+}
+
+// Points lo...hi, inclusive
+type indexRange struct {
+	lo int
+	hi int
+}
+
+func classicWorker(ctx context.Context, run *solverRun, store PointStore, candidates map[int]bool, coverageTracker []int,
+	groupTracker []int, capTracker []int, lo int, hi int) (*gainResult, error) {
+	// Query the points in range lo...hi
+	result := setEmptyResult()
+	it, err := store.RangeIterator(lo, hi)
+	if err != nil {
+		return result, err
+	}
+	defer it.Close()
+
+	// Iterate over points found by the query
+	for it.Next() { // Iterate over query results
+		if err := ctx.Err(); err != nil { // Another worker failed
+			return result, err
+		}
+		point, err := it.Point()
+		if err != nil {
+			return result, err
+		}
+		// If the point is a candidate AND it is assigned to this worker thread
+		if candidates[point.Index] && !saturated(&point, capTracker) {
+			gain := marginalGain(run, point, coverageTracker, groupTracker, 1)
+			candidate := gainResult{
+				index:    point.Index,
+				gain:     gain,
+				priority: run.priority(point.Index, gain),
+				tieKey:   run.tieKey(&point, groupTracker),
+			}
+			if preferred(&candidate, result) { // Update if better marginal gain found
+				*result = candidate
+			}
+		}
+	}
+	return result, it.Err()
+}
@@ -0,0 +1,104 @@
+package fkc
+
+import (
+	"context"
+	"math"
+	"strconv"
+)
+
+func disCover(run *solverRun, store PointStore, coverageTracker []int,
+	groupTracker []int, capTracker []int, candidates map[int]bool, budget int, threads int, alpha float64,
+	print bool, cp *checkpointer) ([]int, []float64, error) {
+	report("Executing DisCover...\n", print)
+	coreset := make([]int, 0)
+	gains := make([]float64, 0)
+	lambda := 1.0 / math.Sqrt(float64(threads))
+	cardinalityConstraint := 2
+	start := 1
+	if resume := cp.take(); resume != nil { // Continue where the checkpoint left off
+		coreset, gains = resume.stageCoreset(), resume.stageGains()
+		candidates = sliceToSet(resume.Candidates)
+		cardinalityConstraint = resume.Cardinality
+		start = resume.Round + 1
+	}
+
+	// Main logic loop
+	report("Entering the main loop...\n", print)
+	round := start - 1
+	state := func(c *Checkpoint) {
+		c.Candidates = mapToSlice(candidates)
+		c.Cardinality = cardinalityConstraint
+		c.Round = round
+	}
+	for r := start; !run.targetMet(coverageTracker, groupTracker) && (budget < 0 || len(coreset) < budget); r++ {
+		round = r - 1
+		if err := cp.save(coreset, gains, coverageTracker, groupTracker, capTracker, state); err != nil {
+			return coreset, gains, err
+		}
+		if err := run.ctx.Err(); err != nil { // Cancelled or timed out
+			return coreset, gains, err
+		}
+		// Run DisCover subroutine, without overrunning the budget
+		roundConstraint := cardinalityConstraint
+		if budget >= 0 {
+			roundConstraint = min(roundConstraint, budget-len(coreset))
+		}
+		remainingBefore := remainingScore(run, coverageTracker, groupTracker)
+		// The round works on copies of the trackers, so that an interrupted
+		// round leaves the previous round's state to checkpoint
+		roundCoverage := append([]int(nil), coverageTracker...)
+		roundGroups := append([]int(nil), groupTracker...)
+		roundCaps := append([]int(nil), capTracker...)
+		newSet, newGains, err := greeDi(run, candidates, roundCoverage, roundGroups, roundCaps, threads, roundConstraint, store)
+		if err != nil {
+			return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+		}
+		copy(coverageTracker, roundCoverage)
+		copy(groupTracker, roundGroups)
+		copy(capTracker, roundCaps)
+		coreset = append(coreset, newSet...)
+		gains = append(gains, newGains...)
+		if len(newSet) == 0 { // Every remaining candidate is in a saturated group
+			break
+		}
+		candidates = deleteAllFromSet(candidates, newSet)
+		remainingAfter := remainingScore(run, coverageTracker, groupTracker)
+		// Decide whether to double cardinality coustraint or not
+		if remainingBefore-remainingAfter < alpha*lambda*remainingBefore {
+			cardinalityConstraint *= 2 // Double if marginal gain is too small
+		}
+
+		report("\rRound: "+strconv.Itoa(r)+", remaining candidates: "+strconv.Itoa(len(candidates)), print)
+	}
+	report("\n", print)
+	return coreset, gains, nil
+}
+
+func greeDi(run *solverRun, candidates map[int]bool, coverageTracker []int, groupTracker []int,
+	capTracker []int, threads int, cardinalityConstraint int, store PointStore) ([]int, []float64, error) {
+	// Split candidates into subsets
+	splitCandidates := splitSet(candidates, threads)
+
+	// Call centralized greedy as goroutines with split candidates. Each gets
+	// its own copy of the trackers since we don't want to mess with them.
+	results, err := parallelMap(run.ctx, threads, splitCandidates,
+		func(ctx context.Context, split map[int]bool) ([]int, error) {
+			solution, _, err := lazyGreedy(run, store, append([]int(nil), coverageTracker...), append([]int(nil), groupTracker...),
+				append([]int(nil), capTracker...), split, cardinalityConstraint, 1, false, nil)
+			return solution, err
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Filtered candidates = union of solutions from each thread
+	filteredCandidates := make(map[int]bool, cardinalityConstraint*threads)
+	for _, res := range results {
+		for i := 0; i < len(res); i++ {
+			filteredCandidates[res[i]] = true
+		}
+	}
+
+	// Run centralized greedy on the filtered candidates
+	return lazyGreedy(run, store, coverageTracker, groupTracker, capTracker, filteredCandidates, cardinalityConstraint, threads, false, nil)
+}
@@ -0,0 +1,316 @@
+package fkc
+
+import (
+	"fmt"
+	"math"
+)
+
+/**
+Feasibility pre-check. A group requirement cannot be met if the group has
+fewer points than required, and a coverage requirement cannot be met if the
+point has fewer neighbors than required. A group requirement also cannot be
+met if the group's cap allows fewer points than required, and a coverage
+requirement if the caps allow too few of the point's neighbors to be selected.
+All of these are detected in one pass over the store before any algorithm runs.
+*/
+
+type Feasibility struct {
+	GroupSizes       []int // Number of points in each group
+	GroupReqs        []int // Requirement of each group, including GroupShare
+	GroupCaps        []int // Cap of each group, negative if uncapped, or nil without caps
+	InfeasibleGroups []int // Groups with fewer points than their requirement
+	CappedGroups     []int // Groups whose cap is below their requirement
+	InfeasibleNodes  []int // Points with fewer neighbors than their coverage requirement
+	CappedNodes      []int // Points whose selectable neighbors under the caps fall short of their coverage requirement
+}
+
+func (f *Feasibility) Feasible() bool {
+	return len(f.InfeasibleGroups) == 0 && len(f.CappedGroups) == 0 && len(f.InfeasibleNodes) == 0 &&
+		len(f.CappedNodes) == 0
+}
+
+// Returned by Solve when requirements cannot be met, Options.Clip is off and
+// there is no budget
+type InfeasibleError struct {
+	Feasibility *Feasibility
+}
+
+func (e *InfeasibleError) Error() string {
+	const shown = 10 // Nodes listed in the message
+	f := e.Feasibility
+	nodes := fmt.Sprint(f.InfeasibleNodes)
+	if len(f.InfeasibleNodes) > shown {
+		nodes = fmt.Sprint(f.InfeasibleNodes[:shown]) + "..."
+	}
+	capped := fmt.Sprint(f.CappedNodes)
+	if len(f.CappedNodes) > shown {
+		capped = fmt.Sprint(f.CappedNodes[:shown]) + "..."
+	}
+	return fmt.Sprintf("infeasible requirements: groups %v have too few points, groups %v are capped below their requirement, %d nodes have too few neighbors %s, %d nodes have too few selectable neighbors under the caps %s",
+		f.InfeasibleGroups, f.CappedGroups, len(f.InfeasibleNodes), nodes, len(f.CappedNodes), capped)
+}
+
+// Reports which requirements in opts cannot be met by the points in store
+func CheckFeasibility(store PointStore, opts Options) (*Feasibility, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	n, err := store.Size()
+	if err != nil {
+		return nil, err
+	}
+	scan, err := scanPoints(store, n, opts)
+	if err != nil {
+		return nil, err
+	}
+	return checkFeasibility(scan, opts), nil
+}
+
+// What one pass over the store learns about the data and its requirements
+type pointScan struct {
+	degrees      []int     // Number of neighbors of each point
+	coverageReqs []int     // Coverage requirement of each point, before clipping
+	groupSizes   []int     // Number of points in each group
+	groupReqs    []int     // Requirement of each group, before clipping
+	groupCaps    []int     // Cap of each group, negative if uncapped, or nil without caps
+	costs        []float64 // Selection cost of each point
+	coverers     []int     // Number of points listing each point as a neighbor
+	reachable    []int     // Most of those the caps allow to be selected together, or nil without caps
+}
+
+func scanPoints(store PointStore, n int, opts Options) (*pointScan, error) {
+	scan := &pointScan{
+		degrees:      make([]int, n),
+		coverageReqs: make([]int, n),
+		groupSizes:   make([]int, 0),
+		costs:        make([]float64, n),
+		coverers:     make([]int, n),
+	}
+	// Per point, the capped groups its coverers are charged to & how many
+	// coverers each holds, along with the uncapped coverers
+	var cappedCoverers []map[int]int
+	var freeCoverers []int
+	if len(opts.GroupCaps) != 0 {
+		cappedCoverers = make([]map[int]int, n)
+		freeCoverers = make([]int, n)
+	}
+	it, err := store.FullIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for i := 0; it.Next(); i++ {
+		point, err := it.Point()
+		if err != nil {
+			return nil, err
+		}
+		if point.Index < 0 || point.Index >= n {
+			return nil, storeError("scan points", point.Index, fmt.Errorf("index outside of 0...%d", n-1))
+		}
+		for _, group := range point.Groups {
+			if group < 0 {
+				return nil, storeError("scan points", point.Index, fmt.Errorf("negative group %d", group))
+			}
+			for len(scan.groupSizes) <= group {
+				scan.groupSizes = append(scan.groupSizes, 0)
+			}
+			scan.groupSizes[group]++
+		}
+		var badNeighbor error // Would crash the gain evaluations
+		point.forEachNeighbor(func(neighbor int) {
+			if (neighbor < 0 || neighbor >= n) && badNeighbor == nil {
+				badNeighbor = fmt.Errorf("neighbor %d outside of 0...%d", neighbor, n-1)
+			}
+		})
+		if badNeighbor != nil {
+			return nil, storeError("scan points", point.Index, badNeighbor)
+		}
+		scan.degrees[point.Index] = point.degree()
+		tightest := tightestCap(&point, opts.GroupCaps)
+		point.forEachNeighbor(func(neighbor int) {
+			scan.coverers[neighbor]++
+			if cappedCoverers == nil {
+				return
+			}
+			if tightest < 0 {
+				freeCoverers[neighbor]++
+				return
+			}
+			if cappedCoverers[neighbor] == nil {
+				cappedCoverers[neighbor] = make(map[int]int)
+			}
+			cappedCoverers[neighbor][tightest]++
+		})
+		scan.coverageReqs[point.Index] = coverageRequirement(&point, opts)
+		if scan.coverageReqs[point.Index] < 0 {
+			return nil, storeError("scan points", point.Index, fmt.Errorf("negative coverage requirement %d", *point.Coverage))
+		}
+		scan.costs[point.Index] = pointCost(&point, opts)
+		if !(scan.costs[point.Index] > 0) || math.IsInf(scan.costs[point.Index], 1) {
+			return nil, storeError("scan points", point.Index, fmt.Errorf("cost %v is not a finite positive number", scan.costs[point.Index]))
+		}
+		report(fmt.Sprintf("\rScan iteration %d", i), opts.Print)
+	}
+	report("\n", opts.Print)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	scan.groupReqs, err = groupRequirements(opts, scan.groupSizes)
+	if err != nil {
+		return nil, err
+	}
+	scan.groupCaps, err = groupCaps(opts, scan.groupSizes)
+	if err != nil || scan.groupCaps == nil {
+		return scan, err
+	}
+	scan.reachable = make([]int, n)
+	for i := range scan.reachable {
+		scan.reachable[i] = freeCoverers[i]
+		for group, count := range cappedCoverers[i] {
+			scan.reachable[i] += min(count, scan.groupCaps[group])
+		}
+	}
+	return scan, nil
+}
+
+// The point's group with the lowest nonnegative cap, which bounds how many
+// points like it can be selected, or -1 if none of its groups is capped
+func tightestCap(point *Point, caps []int) int {
+	tightest := -1
+	for _, group := range point.Groups {
+		if group >= len(caps) || caps[group] < 0 {
+			continue
+		}
+		if tightest < 0 || caps[group] < caps[tightest] {
+			tightest = group
+		}
+	}
+	return tightest
+}
+
+// A point's coverage requirement is its CoverageReqs entry if there is one,
+// otherwise the coverage field of its document, otherwise CoverageReq
+func coverageRequirement(point *Point, opts Options) int {
+	if req, ok := opts.CoverageReqs[point.Index]; ok {
+		return req
+	}
+	if point.Coverage != nil {
+		return *point.Coverage
+	}
+	return opts.CoverageReq
+}
+
+// A point's cost is its Costs entry if there is one, otherwise the cost field
+// of its document, otherwise 1
+func pointCost(point *Point, opts Options) float64 {
+	if cost, ok := opts.Costs[point.Index]; ok {
+		return cost
+	}
+	if point.Cost != nil {
+		return *point.Cost
+	}
+	return 1
+}
+
+// Resolves each group's requirement: its GroupReqs entry (zero if GroupReqs
+// is empty), raised to GroupShare of the group's size
+func groupRequirements(opts Options, groupSizes []int) ([]int, error) {
+	if len(opts.GroupReqs) != 0 && len(opts.GroupReqs) != len(groupSizes) {
+		return nil, fmt.Errorf("invalid options: %d group requirements given but the data has %d groups",
+			len(opts.GroupReqs), len(groupSizes))
+	}
+	groupReqs := make([]int, len(groupSizes))
+	for group, size := range groupSizes {
+		if len(opts.GroupReqs) != 0 {
+			groupReqs[group] = opts.GroupReqs[group]
+		}
+		share := int(math.Ceil(opts.GroupShare * float64(size)))
+		groupReqs[group] = max(groupReqs[group], share)
+	}
+	return groupReqs, nil
+}
+
+func groupCaps(opts Options, groupSizes []int) ([]int, error) {
+	if len(opts.GroupCaps) == 0 {
+		return nil, nil
+	}
+	if len(opts.GroupCaps) != len(groupSizes) {
+		return nil, fmt.Errorf("invalid options: %d group caps given but the data has %d groups",
+			len(opts.GroupCaps), len(groupSizes))
+	}
+	caps := make([]int, len(groupSizes))
+	for group, cap := range opts.GroupCaps {
+		caps[group] = max(-1, cap)
+	}
+	return caps, nil
+}
+
+// Without Dense, coverage requirements are already capped by the degree, so
+// only the caps can make them infeasible
+func checkFeasibility(scan *pointScan, opts Options) *Feasibility {
+	f := &Feasibility{
+		GroupSizes:       scan.groupSizes,
+		GroupReqs:        scan.groupReqs,
+		GroupCaps:        scan.groupCaps,
+		InfeasibleGroups: make([]int, 0),
+		CappedGroups:     make([]int, 0),
+		InfeasibleNodes:  make([]int, 0),
+		CappedNodes:      make([]int, 0),
+	}
+	for group, size := range scan.groupSizes {
+		if scan.groupReqs[group] > size {
+			f.InfeasibleGroups = append(f.InfeasibleGroups, group)
+		}
+		if scan.groupCaps != nil && scan.groupCaps[group] >= 0 && scan.groupReqs[group] > scan.groupCaps[group] {
+			f.CappedGroups = append(f.CappedGroups, group)
+		}
+	}
+	if opts.Dense {
+		for index, degree := range scan.degrees {
+			if scan.coverageReqs[index] > degree {
+				f.InfeasibleNodes = append(f.InfeasibleNodes, index)
+			}
+		}
+	}
+	for index, reachable := range scan.reachable { // Only with caps
+		if reachable < min(scan.coverageReqs[index], scan.coverers[index]) {
+			f.CappedNodes = append(f.CappedNodes, index)
+		}
+	}
+	return f
+}
+
+// Initial trackers. With Clip, each requirement is lowered to what the data
+// and the caps allow; this generalizes the degree cap applied when Dense is
+// off. The cap tracker holds the remaining capacity of each group, or is nil
+// without caps.
+func initTrackers(scan *pointScan, opts Options) ([]int, []int, []int) {
+	coverageTracker := make([]int, len(scan.degrees))
+	for i, degree := range scan.degrees {
+		if opts.Dense && !opts.Clip {
+			coverageTracker[i] = scan.coverageReqs[i]
+		} else {
+			coverageTracker[i] = min(degree, scan.coverageReqs[i])
+		}
+		if opts.Clip && scan.reachable != nil {
+			coverageTracker[i] = min(coverageTracker[i], scan.reachable[i])
+		}
+	}
+	groupTracker := make([]int, len(scan.groupSizes))
+	for group, size := range scan.groupSizes {
+		if opts.Clip {
+			groupTracker[group] = min(size, scan.groupReqs[group])
+			if scan.groupCaps != nil && scan.groupCaps[group] >= 0 {
+				groupTracker[group] = min(groupTracker[group], scan.groupCaps[group])
+			}
+		} else {
+			groupTracker[group] = scan.groupReqs[group]
+		}
+	}
+	var capTracker []int
+	if scan.groupCaps != nil {
+		capTracker = make([]int, len(scan.groupCaps))
+		copy(capTracker, scan.groupCaps)
+	}
+	return coverageTracker, groupTracker, capTracker
+}
@@ -0,0 +1,87 @@
+package fkc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+/**
+Group membership. A point may belong to several groups at once, and selecting
+it counts towards the requirement of every one of them.
+*/
+
+// Sorted, duplicate-free ids of the groups a point belongs to. In MongoDB a
+// single group is stored as a plain integer, as collections always have been,
+// and several groups as an array.
+type GroupSet []int
+
+func NewGroupSet(groups ...int) GroupSet {
+	set := make(GroupSet, 0, len(groups))
+	set = append(set, groups...)
+	sort.Ints(set)
+	unique := set[:0]
+	for i, group := range set {
+		if i == 0 || group != set[i-1] {
+			unique = append(unique, group)
+		}
+	}
+	return unique
+}
+
+// Parses a list of group ids separated by commas or whitespace, such as the
+// right side of a group file line
+func ParseGroupSet(s string) (GroupSet, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	groups := make([]int, len(fields))
+	for i, field := range fields {
+		group, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("group id %q: %w", field, err)
+		}
+		groups[i] = group
+	}
+	return NewGroupSet(groups...), nil
+}
+
+func (s GroupSet) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if len(s) == 1 {
+		return bson.MarshalValue(s[0])
+	}
+	return bson.MarshalValue([]int(s))
+}
+
+func (s *GroupSet) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bson.RawValue{Type: t, Value: data}
+	if t == bsontype.Null {
+		*s = GroupSet{}
+		return nil
+	}
+	if group, ok := value.AsInt64OK(); ok { // Single group
+		*s = GroupSet{int(group)}
+		return nil
+	}
+	if t != bsontype.Array {
+		return fmt.Errorf("group: expected integer or array, got BSON type %v", t)
+	}
+	values, err := bson.Raw(data).Values()
+	if err != nil {
+		return err
+	}
+	groups := make([]int, len(values))
+	for i, value := range values {
+		group, ok := value.AsInt64OK()
+		if !ok {
+			return fmt.Errorf("group: unexpected BSON type %v at position %d", value.Type, i)
+		}
+		groups[i] = int(group)
+	}
+	*s = NewGroupSet(groups...)
+	return nil
+}
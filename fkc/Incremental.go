@@ -0,0 +1,63 @@
+package fkc
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+)
+
+/**
+Incremental re-solve. When points are appended to the store, a Solve with
+Options.Previous keeps the previous coreset and only selects what the new
+points require. Appended points must take the indices after the old ones.
+*/
+
+// Saves a result so that a later Solve can extend it
+func SaveResult(fileName string, result *Result) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(file).Encode(result); err != nil {
+		file.Close()
+		return fmt.Errorf("result %s: %w", fileName, err)
+	}
+	return file.Close()
+}
+
+// Reads a result written by SaveResult
+func LoadResult(fileName string) (*Result, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	result := &Result{}
+	if err := gob.NewDecoder(file).Decode(result); err != nil {
+		return nil, fmt.Errorf("result %s: %w", fileName, err)
+	}
+	return result, nil
+}
+
+// Carries the previous result over to the grown store. Old points keep their
+// residual coverage, while new points are credited for the previously selected
+// points that now neighbor them. Groups and caps are charged for every
+// previous selection, since group requirements may have grown with the data.
+// Returns the previous points' gains on the grown store.
+func extendTrackers(run *solverRun, store PointStore, prev *Result, coverageTracker []int, groupTracker []int,
+	capTracker []int) ([]float64, error) {
+	if len(prev.CoverageTracker) > len(coverageTracker) {
+		return nil, fmt.Errorf("previous result has %d points, store only %d", len(prev.CoverageTracker), len(coverageTracker))
+	}
+	for _, index := range prev.Coreset {
+		if index < 0 || index >= len(prev.CoverageTracker) {
+			return nil, fmt.Errorf("previous coreset point %d out of range", index)
+		}
+	}
+	gains, err := warmStart(run, store, prev.Coreset, coverageTracker, groupTracker, capTracker)
+	if err != nil {
+		return nil, err
+	}
+	copy(coverageTracker, prev.CoverageTracker)
+	return gains, nil
+}
@@ -0,0 +1,109 @@
+package fkc
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"strconv"
+)
+
+/**
+Runs the
+*/
+
+func lazyGreedy(run *solverRun, store PointStore, coverageTracker []int,
+	groupTracker []int, capTracker []int, candidates map[int]bool, constraint int, threads int,
+	print bool, cp *checkpointer) ([]int, []float64, error) {
+	report("Executing lazy greedy algorithm...\n", print)
+	report("remaining score: "+fmt.Sprint(remainingScore(run, coverageTracker, groupTracker))+"\n", print)
+
+	// Initialize sets, continuing where the checkpoint left off if any
+	coreset := make([]int, 0)
+	gains := make([]float64, 0)
+	var candidatesPQ priorityQueue
+	if resume := cp.take(); resume != nil {
+		coreset, gains = resume.stageCoreset(), resume.stageGains()
+		candidatesPQ = queueFromEntries(resume.Bounds)
+	} else {
+		var err error
+		candidatesPQ, err = initialQueue(run, store, coverageTracker, groupTracker, capTracker, candidates, threads)
+		if err != nil {
+			return coreset, gains, err
+		}
+	}
+
+	// The queue keeps valid upper bounds through an interrupted iteration, as
+	// long as every popped candidate is either pushed back or saturated
+	state := func(c *Checkpoint) {
+		c.Bounds = candidatesPQ.entries()
+	}
+
+	// Repeat main loop until all trackers are complete, or the candidate pool
+	// is dried out, or cardinality constraint is met
+	report("Entering the main loop...\n", print)
+	for i := len(coreset); !run.targetMet(coverageTracker, groupTracker) && len(candidatesPQ) > 0 && (constraint < 0 || len(coreset) < constraint); i++ {
+		if err := cp.save(coreset, gains, coverageTracker, groupTracker, capTracker, state); err != nil {
+			return coreset, gains, err
+		}
+		if err := run.ctx.Err(); err != nil { // Cancelled or timed out
+			return coreset, gains, err
+		}
+		for j := 1; true; j++ {
+			// Get the next candidate point & its marginal gain
+			popped := heap.Pop(&candidatesPQ).(*pqItem)
+			index := popped.value
+			point, err := store.GetPoint(index)
+			if err != nil {
+				heap.Push(&candidatesPQ, popped)
+				return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+			}
+			if saturated(&point, capTracker) { // Drop for good, caps only tighten
+				if len(candidatesPQ) == 0 {
+					break
+				}
+				continue
+			}
+			gain := marginalGain(run, point, coverageTracker, groupTracker, threads)
+			priority := run.priority(index, gain)
+			tieKey := run.tieKey(&point, groupTracker)
+
+			// Optimal element found if it's the last possible option or
+			// if its marginal gain is optimal, ties included
+			if len(candidatesPQ) == 0 || !candidatesPQ.topOutranks(priority, tieKey, index) {
+				coreset = append(coreset, index)
+				gains = append(gains, gain)
+				decrementTrackers(&point, coverageTracker, groupTracker, capTracker)
+				report("\rIteration "+strconv.Itoa(i)+" complete with marginal gain "+fmt.Sprint(gain)+", remaining candidates: "+strconv.Itoa(len(candidatesPQ))+", and elements reevaluated: "+strconv.Itoa(j), print)
+				break // End search
+			} else { // Add the point back to heap with updated marginal gain
+				item := &pqItem{
+					value:    index,
+					priority: priority,
+					tieKey:   tieKey,
+				}
+				heap.Push(&candidatesPQ, item)
+			}
+		}
+	}
+	report("\n", print)
+	return coreset, gains, nil
+}
+
+// Builds the priority queue of the candidates' initial marginal gains
+func initialQueue(run *solverRun, store PointStore, coverageTracker []int, groupTracker []int,
+	capTracker []int, candidates map[int]bool, threads int) (priorityQueue, error) {
+	splitCandidates := splitSet(candidates, threads)
+	initialGains, err := parallelMap(run.ctx, threads, splitCandidates,
+		func(ctx context.Context, split map[int]bool) ([]*pqItem, error) {
+			return getMarginalGains(ctx, run, store, coverageTracker, groupTracker, capTracker, split)
+		})
+	if err != nil {
+		return nil, err
+	}
+	candidatesPQ := make(priorityQueue, 0, len(candidates))
+	for _, items := range initialGains {
+		candidatesPQ = append(candidatesPQ, items...)
+	}
+	heap.Init(&candidatesPQ)
+	return candidatesPQ, nil
+}
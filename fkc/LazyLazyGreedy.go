@@ -0,0 +1,157 @@
+package fkc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+func lazyLazyGreedy(run *solverRun, store PointStore, coverageTracker []int,
+	groupTracker []int, capTracker []int, candidates map[int]bool, constraint int, threads int,
+	print bool, eps float64, objRatio float64, cp *checkpointer) ([]int, []float64, error) {
+	report("Executing lazylazy greedy algorithm...\n", print)
+
+	// Initialize sets & constants
+	n := len(candidates)
+	if constraint < 0 { // If cardinality constraint flag was set to -1, then
+		constraint = n // cardinality constraint is equal to n (i.e. no bound)
+	}
+	s := int(eps * float64(n))
+	coreset := make([]int, 0)
+	gains := make([]float64, 0)
+	initialObj := remainingScore(run, coverageTracker, groupTracker)
+	objScore := (1 - objRatio) * initialObj
+	start := 0
+	if resume := cp.take(); resume != nil { // Continue where the checkpoint left off
+		coreset, gains = resume.stageCoreset(), resume.stageGains()
+		candidates = sliceToSet(resume.Candidates)
+		constraint, s, objScore = resume.Constraint, resume.SampleSize, resume.ObjScore
+		start = resume.Round
+	}
+
+	// The state as of the start of the current iteration, before its sample
+	// was drawn
+	round, draws := start, run.source.draws
+	state := func(c *Checkpoint) {
+		c.Candidates = mapToSlice(candidates)
+		c.Constraint, c.SampleSize, c.ObjScore = constraint, s, objScore
+		c.Round, c.RandomDraws = round, draws
+	}
+
+	// Repeat main loop until all trackers are complete, or the candidate pool
+	// is dried out, or cardinality constraint is met
+	report("Entering the main loop...\n", print)
+
+	for i := start; (len(coreset) < constraint) && (remainingScore(run, coverageTracker, groupTracker) >= objScore) && !run.targetMet(coverageTracker, groupTracker); i++ {
+		round, draws = i, run.source.draws
+		if err := cp.save(coreset, gains, coverageTracker, groupTracker, capTracker, state); err != nil {
+			return coreset, gains, err
+		}
+		if err := run.ctx.Err(); err != nil { // Cancelled or timed out
+			return coreset, gains, err
+		}
+		// Take a subsample of the candidates, never empty while any remain
+		sample := subSampleSet(candidates, max(1, min(s, len(candidates))), run.rng)
+		splitSample := splitSet(sample, threads)
+
+		// Actual work of concurrent candidate evaluation
+		results, err := parallelMap(run.ctx, threads, splitSample,
+			func(ctx context.Context, sample map[int]bool) (*gainResult, error) {
+				return lazyLazyWorker(ctx, run, store, sample, coverageTracker, groupTracker, capTracker)
+			})
+		if err != nil {
+			return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+		}
+		chosen := getBestResult(results)
+		if chosen.index < 0 { // The whole sample is in saturated groups
+			pruned, err := pruneSaturated(store, candidates, capTracker)
+			if err != nil {
+				return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+			}
+			if pruned == 0 || len(candidates) == 0 { // Nothing left to sample
+				break
+			}
+			continue
+		}
+
+		// Bookkeeping
+		point, err := store.GetPoint(chosen.index)
+		if err != nil {
+			return coreset, gains, cp.saveOnCancel(err, coreset, gains, coverageTracker, groupTracker, capTracker, state)
+		}
+		coreset = append(coreset, chosen.index)
+		gains = append(gains, chosen.gain)
+		decrementTrackers(&point, coverageTracker, groupTracker, capTracker)
+		delete(candidates, chosen.index)
+		report("\rIteration "+strconv.Itoa(i)+" complete with marginal gain "+fmt.Sprint(chosen.gain)+", remaining candidates"+strconv.Itoa(len(candidates)), print)
+	}
+	report("\n", print)
+	return coreset, gains, nil
+}
+
+func lazyLazyWorker(ctx context.Context, run *solverRun, store PointStore, candidates map[int]bool, coverageTracker []int,
+	groupTracker []int, capTracker []int) (*gainResult, error) {
+	// Query the points in range lo...hi
+	result := setEmptyResult()
+	it, err := store.SetIterator(candidates)
+	if err != nil {
+		return result, err
+	}
+	defer it.Close()
+
+	// Iterate over points found by the query
+	for it.Next() { // Iterate over query results
+		if err := ctx.Err(); err != nil { // Another worker failed
+			return result, err
+		}
+		point, err := it.Point()
+		if err != nil {
+			return result, err
+		}
+		if saturated(&point, capTracker) {
+			continue
+		}
+		gain := marginalGain(run, point, coverageTracker, groupTracker, 1)
+		candidate := gainResult{
+			index:    point.Index,
+			gain:     gain,
+			priority: run.priority(point.Index, gain),
+			tieKey:   run.tieKey(&point, groupTracker),
+		}
+		if preferred(&candidate, result) { // Update if better marginal gain found
+			*result = candidate
+		}
+	}
+	return result, it.Err()
+}
+
+// Removes the candidates that can no longer be selected because one of their
+// groups is saturated, and returns how many were removed. Candidates are left
+// untouched if the store fails.
+func pruneSaturated(store PointStore, candidates map[int]bool, capTracker []int) (int, error) {
+	if capTracker == nil {
+		return 0, nil
+	}
+	it, err := store.SetIterator(candidates)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	pruned := make([]int, 0)
+	for it.Next() {
+		point, err := it.Point()
+		if err != nil {
+			return 0, err
+		}
+		if saturated(&point, capTracker) {
+			pruned = append(pruned, point.Index)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+	for _, index := range pruned {
+		delete(candidates, index)
+	}
+	return len(pruned), nil
+}
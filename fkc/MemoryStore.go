@@ -0,0 +1,251 @@
+package fkc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/**
+PointStore that keeps every point in a slice, indexed by the point's index.
+Meant for graphs that fit in RAM, so that lookups don't pay for a DB query.
+*/
+
+type MemoryStore struct {
+	points []Point
+}
+
+// Builds a store from points whose indices are exactly 0...n-1, each once
+func NewMemoryStore(points []Point) (*MemoryStore, error) {
+	store := &MemoryStore{
+		points: make([]Point, len(points)),
+	}
+	filled := make([]bool, len(points))
+	for _, point := range points {
+		if point.Index < 0 || point.Index >= len(points) {
+			return nil, storeError("load point", point.Index, errors.New("index outside of 0...n-1"))
+		}
+		if filled[point.Index] {
+			return nil, storeError("load point", point.Index, errors.New("duplicate index"))
+		}
+		filled[point.Index] = true
+		store.points[point.Index] = point
+	}
+	return store, nil
+}
+
+// Copies every point of another store (e.g. a MongoDB collection) into memory
+func LoadMemoryStore(source PointStore) (*MemoryStore, error) {
+	n, err := source.Size()
+	if err != nil {
+		return nil, err
+	}
+	it, err := source.FullIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	points := make([]Point, 0, n)
+	for it.Next() {
+		point, err := it.Point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return NewMemoryStore(points)
+}
+
+// Converts every point's neighbor list into a bitset, trading memory on
+// sparse graphs for faster marginal gain evaluation on dense ones
+func (s *MemoryStore) PackNeighbors() {
+	n := len(s.points)
+	for i := range s.points {
+		s.points[i].packNeighbors(n)
+	}
+}
+
+func (s *MemoryStore) Size() (int, error) {
+	return len(s.points), nil
+}
+
+func (s *MemoryStore) GetPoint(index int) (Point, error) {
+	if index < 0 || index >= len(s.points) {
+		return Point{}, storeError("get point", index, errors.New("index out of range"))
+	}
+	return s.points[index], nil
+}
+
+func (s *MemoryStore) FullIterator() (PointIterator, error) {
+	return &memoryIterator{points: s.points, pos: -1}, nil
+}
+
+func (s *MemoryStore) RangeIterator(lo int, hi int) (PointIterator, error) {
+	lo = max(0, lo)
+	hi = min(len(s.points)-1, hi)
+	if lo > hi {
+		return &memoryIterator{pos: -1}, nil
+	}
+	return &memoryIterator{points: s.points[lo : hi+1], pos: -1}, nil
+}
+
+func (s *MemoryStore) SetIterator(set map[int]bool) (PointIterator, error) {
+	indices := mapToSlice(set)
+	points := make([]Point, 0, len(indices))
+	for _, index := range indices {
+		if index >= 0 && index < len(s.points) {
+			points = append(points, s.points[index])
+		}
+	}
+	return &memoryIterator{points: points, pos: -1}, nil
+}
+
+type memoryIterator struct {
+	points []Point
+	pos    int
+}
+
+func (it *memoryIterator) Next() bool {
+	it.pos++
+	return it.pos < len(it.points)
+}
+
+func (it *memoryIterator) Point() (Point, error) {
+	return it.points[it.pos], nil
+}
+
+func (it *memoryIterator) Err() error {
+	return nil
+}
+
+func (it *memoryIterator) Close() error {
+	return nil
+}
+
+/**
+Loading points from text files, in the same format TxTtoDB consumes:
+the adjacency file has entries "index : { neighbor, neighbor, ..., neighbor}"
+(possibly spanning several lines), and the group file has lines
+"index : group" or, for points in several groups, "index : group, group".
+*/
+
+// Reads the adjacency and group files into memory. Both files must list the
+// same indices, exactly 0...n-1.
+func LoadMemoryStoreFromFiles(adjFileName string, groupFileName string) (*MemoryStore, error) {
+	adjLists, err := parseAdjFile(adjFileName)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := parseGroupFile(groupFileName)
+	if err != nil {
+		return nil, err
+	}
+	n := len(adjLists)
+	points := make([]Point, n)
+	for i := 0; i < n; i++ {
+		if _, ok := adjLists[i]; !ok {
+			return nil, storeError("load "+adjFileName, i, errors.New("index missing, indices must be 0...n-1"))
+		}
+		if _, ok := groups[i]; !ok {
+			return nil, storeError("load "+groupFileName, i, errors.New("index missing from the group file"))
+		}
+		points[i] = Point{
+			Index:     i,
+			Groups:    groups[i],
+			Neighbors: adjLists[i],
+		}
+	}
+	if len(groups) != n {
+		return nil, storeError("load "+groupFileName, -1,
+			fmt.Errorf("lists %d indices, but the adjacency file lists %d", len(groups), n))
+	}
+	return NewMemoryStore(points)
+}
+
+func parseAdjFile(fileName string) (map[int][]int, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, storeError("open adjacency file", -1, err)
+	}
+	defer file.Close()
+
+	adjLists := make(map[int][]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	entry := ""
+	for scanner.Scan() {
+		if entry != "" { // A line break separates neighbors, like a comma
+			entry += ","
+		}
+		entry += scanner.Text()
+		if !strings.Contains(entry, "}") { // Entry continues on the next line
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) < 2 {
+			return nil, storeError("parse "+fileName, -1, fmt.Errorf("malformed adjacency entry %q", entry))
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, storeError("parse "+fileName, -1, err)
+		}
+		neighbors := make([]int, 0)
+		for _, v := range strings.Split(strings.Trim(parts[1], "{} \t"), ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			neighbor, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, storeError("parse "+fileName, index, err)
+			}
+			neighbors = append(neighbors, neighbor)
+		}
+		adjLists[index] = neighbors
+		entry = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, storeError("read "+fileName, -1, err)
+	}
+	return adjLists, nil
+}
+
+func parseGroupFile(fileName string) (map[int]GroupSet, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, storeError("open group file", -1, err)
+	}
+	defer file.Close()
+
+	groups := make(map[int]GroupSet)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) < 2 {
+			return nil, storeError("parse "+fileName, -1, fmt.Errorf("malformed group entry %q", line))
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, storeError("parse "+fileName, -1, err)
+		}
+		set, err := ParseGroupSet(parts[1])
+		if err != nil {
+			return nil, storeError("parse "+fileName, index, err)
+		}
+		groups[index] = set
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, storeError("read "+fileName, -1, err)
+	}
+	return groups, nil
+}
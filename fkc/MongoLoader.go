@@ -0,0 +1,167 @@
+package fkc
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/**
+PointStore backed by a MongoDB collection.
+*/
+
+type MongoStore struct {
+	collection *mongo.Collection
+	ctx        context.Context // Context of every query
+}
+
+// Opens the named collection on the local MongoDB server
+func NewMongoStore(dbName string, collectionName string) (*MongoStore, error) {
+	collection, err := getMongoCollection(dbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection, ctx: context.Background()}, nil
+}
+
+func (s *MongoStore) WithContext(ctx context.Context) PointStore {
+	return &MongoStore{collection: s.collection, ctx: ctx}
+}
+
+func (s *MongoStore) Size() (int, error) {
+	return getCollectionSize(s.ctx, s.collection)
+}
+
+func (s *MongoStore) GetPoint(index int) (Point, error) {
+	return getPointFromDB(s.ctx, s.collection, index)
+}
+
+func (s *MongoStore) FullIterator() (PointIterator, error) {
+	cur, err := getFullCursor(s.ctx, s.collection)
+	if err != nil {
+		return nil, err
+	}
+	return &mongoIterator{cur: cur, ctx: s.ctx}, nil
+}
+
+func (s *MongoStore) RangeIterator(lo int, hi int) (PointIterator, error) {
+	cur, err := getRangeCursor(s.ctx, s.collection, lo, hi)
+	if err != nil {
+		return nil, err
+	}
+	return &mongoIterator{cur: cur, ctx: s.ctx}, nil
+}
+
+func (s *MongoStore) SetIterator(set map[int]bool) (PointIterator, error) {
+	cur, err := getSetCursor(s.ctx, s.collection, set)
+	if err != nil {
+		return nil, err
+	}
+	return &mongoIterator{cur: cur, ctx: s.ctx}, nil
+}
+
+type mongoIterator struct {
+	cur *mongo.Cursor
+	ctx context.Context
+}
+
+func (it *mongoIterator) Next() bool {
+	return it.cur.Next(it.ctx)
+}
+
+func (it *mongoIterator) Point() (Point, error) {
+	return getEntryFromCursor(it.cur)
+}
+
+func (it *mongoIterator) Err() error {
+	return storeError("iterate cursor", -1, it.cur.Err())
+}
+
+func (it *mongoIterator) Close() error {
+	return storeError("close cursor", -1, it.cur.Close(it.ctx))
+}
+
+/**
+Importing a MongoDB Collection.
+*/
+
+func getMongoCollection(dbName string, collectionName string) (*mongo.Collection, error) {
+	// Create mongoDB server connection
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		return nil, storeError("connect to "+dbName+"."+collectionName, -1, err)
+	}
+
+	// Create handles
+	db := client.Database(dbName)
+	collection := db.Collection(collectionName)
+
+	return collection, nil
+}
+
+func getFullCursor(ctx context.Context, collection *mongo.Collection) (*mongo.Cursor, error) {
+	cur, err := collection.Find(ctx, bson.M{})
+	return cur, storeError("find all points", -1, err)
+}
+
+func getEntryFromCursor(cur *mongo.Cursor) (Point, error) {
+	var entry Point
+	err := cur.Decode(&entry)
+	return entry, storeError("decode point", -1, err)
+}
+
+func getSetCursor(ctx context.Context, collection *mongo.Collection, set map[int]bool) (*mongo.Cursor, error) {
+	return getSliceCursor(ctx, collection, mapToSlice(set))
+}
+
+func getSliceCursor(ctx context.Context, collection *mongo.Collection, slice []int) (*mongo.Cursor, error) {
+	filter := bson.M{
+		"index": bson.M{
+			"$in": slice,
+		},
+	}
+	return getCursorFilter(ctx, collection, filter)
+}
+
+func getRangeCursor(ctx context.Context, collection *mongo.Collection, min int, max int) (*mongo.Cursor, error) {
+	filter := bson.M{
+		"index": bson.M{
+			"$gte": min,
+			"$lte": max,
+		},
+	}
+	return getCursorFilter(ctx, collection, filter)
+}
+
+func getCursorFilter(ctx context.Context, collection *mongo.Collection, filter bson.M) (*mongo.Cursor, error) {
+	cur, err := collection.Find(ctx, filter)
+	return cur, storeError("find points", -1, err)
+}
+
+func getCollectionSize(ctx context.Context, collection *mongo.Collection) (int, error) {
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	return int(count), storeError("count points", -1, err)
+}
+
+func getPointFromDB(ctx context.Context, collection *mongo.Collection, index int) (Point, error) {
+	var p Point
+	filter := bson.M{"index": index}
+	err := collection.FindOne(ctx, filter).Decode(&p)
+	return p, storeError("get point", index, err)
+}
+
+/**
+Test method for this file's methods.
+*/
+/*
+func main() {
+	dbName := "dummydb"
+	collectionName := "n1000d3m5r20"
+	collection := getMongoCollection(dbName, collectionName)
+	graph := getFullGraph(collection)
+	fmt.Printf("%v\n", graph)
+}
+*/
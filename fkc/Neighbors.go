@@ -0,0 +1,151 @@
+package fkc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/**
+Sparse adjacency representation. A point stores the indices of its neighbors
+rather than an n-length row of the adjacency matrix, so documents grow with
+the point's degree instead of the size of the graph.
+*/
+
+// Indices of a point's neighbors. Collections written before the sparse
+// representation store an n-length []bool instead; those are converted to
+// index lists while decoding so that they remain usable without migration.
+type NeighborList []int
+
+func (l *NeighborList) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.Null {
+		*l = NeighborList{}
+		return nil
+	}
+	if t != bsontype.Array {
+		return fmt.Errorf("neighbors: expected array, got BSON type %v", t)
+	}
+	values, err := bson.Raw(data).Values()
+	if err != nil {
+		return err
+	}
+	list := make(NeighborList, 0, len(values))
+	for i, value := range values {
+		if adjacent, ok := value.BooleanOK(); ok { // Legacy dense row
+			if adjacent {
+				list = append(list, i)
+			}
+		} else if neighbor, ok := value.AsInt64OK(); ok { // Sparse index
+			list = append(list, int(neighbor))
+		} else {
+			return fmt.Errorf("neighbors: unexpected BSON type %v at position %d", value.Type, i)
+		}
+	}
+	*l = list
+	return nil
+}
+
+/**
+Bitset adjacency representation, for dense graphs where index lists would be
+larger than the adjacency row itself. Neighbor j is bit j%64 of word j/64.
+*/
+
+type Bitset []uint64
+
+func newBitset(n int) Bitset {
+	return make(Bitset, (n+63)/64)
+}
+
+func bitsetFromList(n int, list []int) Bitset {
+	b := newBitset(n)
+	for _, i := range list {
+		b.set(i)
+	}
+	return b
+}
+
+func (b Bitset) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func (b Bitset) has(i int) bool {
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Number of set bits
+func (b Bitset) count() int {
+	count := 0
+	for _, word := range b {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Calls f on the index of every set bit, in increasing order
+func (b Bitset) forEach(f func(i int)) {
+	for w, word := range b {
+		for word != 0 {
+			f(w*64 + bits.TrailingZeros64(word))
+			word &= word - 1 // Clear lowest set bit
+		}
+	}
+}
+
+// Bitsets are stored in MongoDB as binary data of little-endian words
+func (b Bitset) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	data := make([]byte, 8*len(b))
+	for w, word := range b {
+		binary.LittleEndian.PutUint64(data[8*w:], word)
+	}
+	return bson.MarshalValue(primitive.Binary{Data: data})
+}
+
+func (b *Bitset) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.Null {
+		*b = nil
+		return nil
+	}
+	_, raw, ok := bson.RawValue{Type: t, Value: data}.BinaryOK()
+	if !ok {
+		return fmt.Errorf("neighborbits: expected binary, got BSON type %v", t)
+	}
+	words := make(Bitset, (len(raw)+7)/8)
+	for i := range raw {
+		words[i/8] |= uint64(raw[i]) << uint(8*(i%8))
+	}
+	*b = words
+	return nil
+}
+
+/**
+Helpers that hide which of the two representations a point uses.
+*/
+
+func (p *Point) degree() int {
+	if p.NeighborBits != nil {
+		return p.NeighborBits.count()
+	}
+	return len(p.Neighbors)
+}
+
+func (p *Point) forEachNeighbor(f func(i int)) {
+	if p.NeighborBits != nil {
+		p.NeighborBits.forEach(f)
+		return
+	}
+	for _, neighbor := range p.Neighbors {
+		f(neighbor)
+	}
+}
+
+// Replaces the neighbor list with an equivalent bitset over n points
+func (p *Point) packNeighbors(n int) {
+	if p.NeighborBits == nil {
+		p.NeighborBits = bitsetFromList(n, p.Neighbors)
+		p.Neighbors = nil
+	}
+}
@@ -0,0 +1,141 @@
+package fkc
+
+import (
+	"fmt"
+	"math"
+)
+
+/**
+Solver configuration. Start from DefaultOptions and override the fields that
+matter, so that new knobs can be added without breaking existing callers.
+*/
+
+type Options struct {
+	CoverageReq  int             // k-coverage requirement of every point
+	CoverageReqs map[int]int     // Requirements of individual points, overriding CoverageReq and the coverage field
+	GroupReqs    []int           // Number of coreset points required from each group, or empty for none
+	GroupShare   float64         // Each group also requires at least this fraction of its size
+	GroupCaps    []int           // Most coreset points allowed from each group, negative for no cap, or empty for none
+	NodeWeights  map[int]float64 // Weight of each point's residual coverage in the objective; unlisted points weigh 1
+	GroupWeights []float64       // Weight of each group's residual requirement, or empty to weigh every group 1
+	Costs        map[int]float64 // Selection costs of individual points, overriding the cost field
+	CostAware    bool            // Pick the best gain per unit of cost instead of the best gain
+	Target       float64         // Fraction of the total requirement to satisfy before stopping, 1 for a full cover
+	Budget       int             // Most points to select, maximizing the requirement met, which implies Clip; negative for no budget
+	Initial      []int           // Points selected before the algorithm runs, such as an earlier coreset; they count towards caps, which they must not exceed, but not the budget
+	Previous     *Result         // Result to extend to points appended to the store since, or nil; excludes Initial
+	Prune        bool            // Drop coreset points that later selections made redundant, keeping the initial ones
+	Algorithm    Algorithm       // Algorithm to run
+	TieBreak     TieBreak        // How classic, lazy and lazylazy greedy choose between equal gains
+	Threads      int             // Number of goroutines evaluating marginal gains
+	Dense        bool            // Whether the graph is denser than the k-coverage requirement
+	Clip         bool            // Lower unachievable requirements instead of failing with InfeasibleError; always on with a budget
+	Eps          float64         // Portion of candidates sampled in each iteration of LazyLazy
+	ObjRatio     float64         // Portion of objective satisfied with LazyLazy before switching to Lazy
+	Alpha        float64         // DisCover doubles its cardinality constraint when a round gains less than this fraction
+	Seed         int64           // Seed of the random sampling; the same seed & thread count select the same coreset
+	Print        bool            // Whether to report each iteration's progress
+
+	Checkpoint      string      // File to periodically write the solver state to, or empty for none
+	CheckpointEvery int         // Selections between two checkpoints
+	Resume          *Checkpoint // State to continue from, as loaded by LoadCheckpoint, or nil to start afresh
+}
+
+func DefaultOptions() Options {
+	return Options{
+		CoverageReq: 1,
+		GroupReqs:   []int{},
+		Target:      1,
+		Budget:      -1,
+		Algorithm:   ClassicGreedy,
+		TieBreak:    LowestIndex,
+		Threads:     1,
+		Dense:       true,
+		Eps:         0.1,
+		ObjRatio:    0.9,
+		Alpha:       0.2,
+		Seed:        1,
+		Print:       false,
+
+		CheckpointEvery: 100,
+	}
+}
+
+// Checks the options on their own. Solve additionally checks that a nonempty
+// GroupReqs, GroupCaps or GroupWeights has one entry per group present in the
+// data.
+func (opts Options) Validate() error {
+	if opts.CoverageReq < 0 {
+		return fmt.Errorf("invalid options: coverage requirement %d is negative", opts.CoverageReq)
+	}
+	for index, req := range opts.CoverageReqs {
+		if req < 0 {
+			return fmt.Errorf("invalid options: coverage requirement %d of point %d is negative", req, index)
+		}
+	}
+	for group, req := range opts.GroupReqs {
+		if req < 0 {
+			return fmt.Errorf("invalid options: requirement %d of group %d is negative", req, group)
+		}
+	}
+	for index, weight := range opts.NodeWeights {
+		if !validWeight(weight) {
+			return fmt.Errorf("invalid options: weight %v of point %d is not a finite nonnegative number", weight, index)
+		}
+	}
+	for group, weight := range opts.GroupWeights {
+		if !validWeight(weight) {
+			return fmt.Errorf("invalid options: weight %v of group %d is not a finite nonnegative number", weight, group)
+		}
+	}
+	for index, cost := range opts.Costs {
+		if !validWeight(cost) || cost == 0 {
+			return fmt.Errorf("invalid options: cost %v of point %d is not a finite positive number", cost, index)
+		}
+	}
+	if opts.Previous != nil && len(opts.Initial) > 0 {
+		return fmt.Errorf("invalid options: initial points and a previous result cannot both be given")
+	}
+	listed := make(map[int]bool, len(opts.Initial))
+	for _, index := range opts.Initial {
+		if index < 0 {
+			return fmt.Errorf("invalid options: initial point %d is negative", index)
+		}
+		if listed[index] {
+			return fmt.Errorf("invalid options: initial point %d is listed twice", index)
+		}
+		listed[index] = true
+	}
+	if opts.GroupShare < 0 || opts.GroupShare > 1 {
+		return fmt.Errorf("invalid options: group share %v not in [0,1]", opts.GroupShare)
+	}
+	if opts.Target <= 0 || opts.Target > 1 {
+		return fmt.Errorf("invalid options: target %v not in (0,1]", opts.Target)
+	}
+	if opts.Algorithm < ClassicGreedy || opts.Algorithm > DisCover {
+		return fmt.Errorf("invalid options: unknown algorithm %v", opts.Algorithm)
+	}
+	if opts.TieBreak < LowestIndex || opts.TieBreak > Underrepresented {
+		return fmt.Errorf("invalid options: unknown tie-break policy %v", opts.TieBreak)
+	}
+	if opts.Threads < 1 {
+		return fmt.Errorf("invalid options: threads %d must be at least 1", opts.Threads)
+	}
+	if opts.Eps <= 0 || opts.Eps > 1 {
+		return fmt.Errorf("invalid options: eps %v not in (0,1]", opts.Eps)
+	}
+	if opts.ObjRatio < 0 || opts.ObjRatio > 1 {
+		return fmt.Errorf("invalid options: objRatio %v not in [0,1]", opts.ObjRatio)
+	}
+	if opts.Alpha <= 0 || opts.Alpha > 1 {
+		return fmt.Errorf("invalid options: alpha %v not in (0,1]", opts.Alpha)
+	}
+	if opts.Checkpoint != "" && opts.CheckpointEvery < 1 {
+		return fmt.Errorf("invalid options: checkpoint interval %d must be at least 1", opts.CheckpointEvery)
+	}
+	return nil
+}
+
+func validWeight(weight float64) bool {
+	return weight >= 0 && !math.IsInf(weight, 1) // NaN fails the comparison
+}
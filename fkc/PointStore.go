@@ -0,0 +1,73 @@
+package fkc
+
+import (
+	"context"
+	"fmt"
+)
+
+/**
+Storage abstraction for the points of a graph. The algorithms only talk to a
+PointStore, so they can run against MongoDB or any other backend.
+*/
+
+type PointStore interface {
+	// Number of points in the store
+	Size() (int, error)
+	// The point with the given index
+	GetPoint(index int) (Point, error)
+	// Iterate over every point in the store
+	FullIterator() (PointIterator, error)
+	// Iterate over points with index in lo...hi (inclusive)
+	RangeIterator(lo int, hi int) (PointIterator, error)
+	// Iterate over points whose index is in the given set
+	SetIterator(set map[int]bool) (PointIterator, error)
+}
+
+// Stores whose queries can be cancelled. Solve binds such a store to its
+// context, so that a cancelled run doesn't keep waiting on the backend.
+type ContextStore interface {
+	PointStore
+	// The same store, with every query issued under ctx
+	WithContext(ctx context.Context) PointStore
+}
+
+type PointIterator interface {
+	// Advances to the next point, returning false once exhausted or on error
+	Next() bool
+	// The point the iterator currently sits on
+	Point() (Point, error)
+	// The error that stopped iteration early, if any
+	Err() error
+	// Releases any resources held by the iterator
+	Close() error
+}
+
+/**
+Errors raised while accessing a PointStore carry the failed operation and,
+when one is involved, the index of the point.
+*/
+
+type StoreError struct {
+	Op    string // Operation that failed, e.g. "get point"
+	Index int    // Index of the point involved, or -1 if none
+	Err   error
+}
+
+func (e *StoreError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("%s (index %d): %v", e.Op, e.Index, e.Err)
+}
+
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
+
+// Wraps err with the operation and index, leaving nil errors untouched
+func storeError(op string, index int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &StoreError{Op: op, Index: index, Err: err}
+}
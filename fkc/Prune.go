@@ -0,0 +1,93 @@
+package fkc
+
+import "sort"
+
+/**
+Reverse-delete pruning. Greedy algorithms often keep early picks that later
+selections made redundant. The pruning pass visits the coreset in reverse gain
+order and drops every point whose removal leaves each requirement met to the
+same extent, so the objective never decreases.
+*/
+
+// Returns the result without the coreset's redundant points, which keep their
+// selection order. The first fixed points, the ones given up front, are never
+// removed. coverageReqs, groupReqs & capReqs are the trackers before any point
+// was selected.
+func pruneCoreset(run *solverRun, store PointStore, result *Result, fixed int,
+	coverageReqs []int, groupReqs []int, capReqs []int) (*Result, error) {
+	// Count how often each requirement is met by the coreset
+	coreset, gains := result.Coreset, result.Gains
+	points := make([]Point, len(coreset))
+	coverCounts := make([]int, len(coverageReqs))
+	groupCounts := make([]int, len(groupReqs))
+	for i, index := range coreset {
+		point, err := store.GetPoint(index)
+		if err != nil {
+			return result, err
+		}
+		points[i] = point
+		point.forEachNeighbor(func(neighbor int) {
+			coverCounts[neighbor]++
+		})
+		for _, group := range point.Groups {
+			groupCounts[group]++
+		}
+	}
+
+	// Lowest gain first; among equal gains, the later pick first
+	order := make([]int, 0, len(coreset)-fixed)
+	for i := len(coreset) - 1; i >= fixed; i-- {
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return gains[order[a]] < gains[order[b]]
+	})
+
+	// Remove points while every requirement they count towards has a surplus
+	removed := make([]bool, len(coreset))
+	for _, i := range order {
+		point := &points[i]
+		redundant := true
+		point.forEachNeighbor(func(neighbor int) {
+			if coverCounts[neighbor] <= coverageReqs[neighbor] {
+				redundant = false
+			}
+		})
+		for _, group := range point.Groups {
+			if groupCounts[group] <= groupReqs[group] {
+				redundant = false
+			}
+		}
+		if !redundant {
+			continue
+		}
+		removed[i] = true
+		point.forEachNeighbor(func(neighbor int) {
+			coverCounts[neighbor]--
+		})
+		for _, group := range point.Groups {
+			groupCounts[group]--
+		}
+	}
+
+	// Select the remaining points again, as their gains grew with the removals
+	pruned := &Result{
+		Coreset:         make([]int, 0, len(coreset)),
+		Gains:           make([]float64, 0, len(coreset)),
+		CoverageTracker: append([]int(nil), coverageReqs...),
+		GroupTracker:    append([]int(nil), groupReqs...),
+		CapTracker:      append([]int(nil), capReqs...),
+	}
+	for i, index := range coreset {
+		if removed[i] {
+			continue
+		}
+		gain := weightedGain(run.weights, points[i], pruned.CoverageTracker, pruned.GroupTracker, 1)
+		pruned.Coreset = append(pruned.Coreset, index)
+		pruned.Gains = append(pruned.Gains, gain)
+		decrementTrackers(&points[i], pruned.CoverageTracker, pruned.GroupTracker, pruned.CapTracker)
+	}
+	pruned.Satisfied = !notSatisfied(pruned.CoverageTracker, pruned.GroupTracker)
+	pruned.Pruned = len(coreset) - len(pruned.Coreset)
+	return pruned, nil
+}
@@ -0,0 +1,179 @@
+package fkc
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/**
+Reading requirements and weights from files that map an id (a group id or a
+point index) to a value. A .json file holds an object such as
+{"0": 10, "1": 25}; any other file is read as CSV with one "id,value" row per
+entry and an optional header.
+*/
+
+// Reads per-group requirements. Groups that don't appear in the file require
+// nothing; the result is only as long as the highest group id in the file, so
+// pad it with zeros to the number of groups before setting Options.GroupReqs.
+func ReadGroupReqs(fileName string) ([]int, error) {
+	reqs, err := readRequirements(fileName, strconv.Atoi)
+	if err != nil {
+		return nil, err
+	}
+	return toGroupSlice(reqs, 0), nil
+}
+
+// Reads per-point coverage requirements, suitable for Options.CoverageReqs
+func ReadCoverageReqs(fileName string) (map[int]int, error) {
+	return readRequirements(fileName, strconv.Atoi)
+}
+
+// Reads per-group weights. Groups that don't appear in the file weigh 1; like
+// ReadGroupReqs, pad the result with ones to the number of groups before
+// setting Options.GroupWeights.
+func ReadGroupWeights(fileName string) ([]float64, error) {
+	weights, err := readRequirements(fileName, parseFloat)
+	if err != nil {
+		return nil, err
+	}
+	return toGroupSlice(weights, 1), nil
+}
+
+// Reads per-point weights, suitable for Options.NodeWeights
+func ReadNodeWeights(fileName string) (map[int]float64, error) {
+	return readRequirements(fileName, parseFloat)
+}
+
+// Reads per-point selection costs, suitable for Options.Costs
+func ReadCosts(fileName string) (map[int]float64, error) {
+	return readRequirements(fileName, parseFloat)
+}
+
+func readRequirements[T int | float64](fileName string, parse func(string) (T, error)) (map[int]T, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var values map[int]T
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		values, err = parseRequirementsJSON[T](file)
+	} else {
+		values, err = parseRequirementsCSV(file, parse)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read requirements from %s: %w", fileName, err)
+	}
+	for id, value := range values {
+		if id < 0 || value < 0 {
+			return nil, fmt.Errorf("read requirements from %s: negative entry %d: %v", fileName, id, value)
+		}
+	}
+	return values, nil
+}
+
+func parseRequirementsJSON[T int | float64](r io.Reader) (map[int]T, error) {
+	var raw map[string]T
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	values := make(map[int]T, len(raw))
+	for key, value := range raw {
+		id, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("id %q: %w", key, err)
+		}
+		values[id] = value
+	}
+	return values, nil
+}
+
+func parseRequirementsCSV[T int | float64](r io.Reader, parse func(string) (T, error)) (map[int]T, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	values := make(map[int]T)
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.Atoi(record[0])
+		if err != nil {
+			if row == 0 { // Header
+				continue
+			}
+			return nil, fmt.Errorf("row %d: id %q: %w", row+1, record[0], err)
+		}
+		value, err := parse(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: value %q: %w", row+1, record[1], err)
+		}
+		values[id] = value
+	}
+}
+
+// Converts a map keyed by group id to a slice, filling in missing groups
+func toGroupSlice[T int | float64](values map[int]T, missing T) []T {
+	numGroups := 0
+	for group := range values {
+		numGroups = max(numGroups, group+1)
+	}
+	result := make([]T, numGroups)
+	for group := range result {
+		result[group] = missing
+	}
+	for group, value := range values {
+		result[group] = value
+	}
+	return result
+}
+
+/**
+Reading a list of point indices, such as a coreset printed by SubmodularCover.
+Indices are separated by commas or whitespace, and may be wrapped in brackets,
+so that a JSON array reads the same way.
+*/
+
+// Reads point indices, suitable for Options.Initial
+func ReadIndices(fileName string) ([]int, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == ',' || r == '[' || r == ']' || unicode.IsSpace(r)
+	})
+	indices := make([]int, len(fields))
+	for i, field := range fields {
+		index, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("read indices from %s: %w", fileName, err)
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("read indices from %s: negative index %d", fileName, index)
+		}
+		indices[i] = index
+	}
+	return indices, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	value, err := strconv.ParseFloat(s, 64)
+	if err == nil && (math.IsNaN(value) || math.IsInf(value, 0)) {
+		return 0, fmt.Errorf("%v is not a finite number", value)
+	}
+	return value, err
+}
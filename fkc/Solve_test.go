@@ -0,0 +1,152 @@
+package fkc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+/**
+Guarantees every algorithm makes: the same seed & thread count select the same
+coreset, and a run resumed from a checkpoint selects the same coreset as an
+uninterrupted one.
+*/
+
+// Symmetric random graph in which every point neighbors itself, with four
+// groups and a few points in two of them
+func testStore(t *testing.T) *MemoryStore {
+	t.Helper()
+	const n, degree = 400, 12
+	rng := rand.New(rand.NewSource(42))
+	adjacent := make([]map[int]bool, n)
+	for i := range adjacent {
+		adjacent[i] = map[int]bool{i: true}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < degree; j++ {
+			neighbor := rng.Intn(n)
+			adjacent[i][neighbor] = true
+			adjacent[neighbor][i] = true
+		}
+	}
+	points := make([]Point, n)
+	for i := range points {
+		groups := NewGroupSet(i % 4)
+		if i%10 == 0 {
+			groups = NewGroupSet(i%4, (i+1)%4)
+		}
+		points[i] = Point{Index: i, Groups: groups, Neighbors: mapToSlice(adjacent[i])}
+	}
+	store, err := NewMemoryStore(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func testOptions(algorithm Algorithm, threads int) Options {
+	opts := DefaultOptions()
+	opts.CoverageReq = 3
+	opts.GroupReqs = []int{20, 20, 20, 20}
+	opts.Algorithm = algorithm
+	opts.Threads = threads
+	opts.Seed = 7
+	opts.Eps = 0.3
+	return opts
+}
+
+// Calls f for every algorithm, single & multithreaded
+func forEachRun(t *testing.T, f func(t *testing.T, opts Options)) {
+	for algorithm := ClassicGreedy; algorithm <= DisCover; algorithm++ {
+		for _, threads := range []int{1, 3} {
+			opts := testOptions(algorithm, threads)
+			t.Run(fmt.Sprintf("%v/%d threads", algorithm, threads), func(t *testing.T) {
+				f(t, opts)
+			})
+		}
+	}
+}
+
+func sameSelection(t *testing.T, got *Result, want *Result) {
+	t.Helper()
+	if !equalSlices(got.Coreset, want.Coreset) {
+		t.Fatalf("coreset %v, want %v", got.Coreset, want.Coreset)
+	}
+	if !equalSlices(got.Gains, want.Gains) {
+		t.Fatalf("gains %v, want %v", got.Gains, want.Gains)
+	}
+}
+
+func TestSameSeedSameCoreset(t *testing.T) {
+	store := testStore(t)
+	forEachRun(t, func(t *testing.T, opts Options) {
+		first, err := Solve(store, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !first.Satisfied {
+			t.Fatalf("requirements not satisfied by %d points", len(first.Coreset))
+		}
+		second, err := Solve(store, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sameSelection(t, second, first)
+	})
+}
+
+// Store that cancels the run once GetPoint was called a given number of times
+type cancellingStore struct {
+	*MemoryStore
+	calls  atomic.Int64
+	after  int64 // Calls before cancelling, or 0 to never cancel
+	cancel context.CancelFunc
+}
+
+func (s *cancellingStore) GetPoint(index int) (Point, error) {
+	if s.calls.Add(1) == s.after {
+		s.cancel()
+	}
+	return s.MemoryStore.GetPoint(index)
+}
+
+func TestResumeMatchesUninterruptedRun(t *testing.T) {
+	store := testStore(t)
+	forEachRun(t, func(t *testing.T, opts Options) {
+		counting := &cancellingStore{MemoryStore: store}
+		want, err := Solve(counting, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Interrupt halfway; only the save on cancellation writes a checkpoint
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		interrupted := &cancellingStore{MemoryStore: store, after: counting.calls.Load() / 2, cancel: cancel}
+		checkpointOpts := opts
+		checkpointOpts.Checkpoint = filepath.Join(t.TempDir(), "checkpoint")
+		checkpointOpts.CheckpointEvery = 1 << 30
+		if _, err := SolveContext(ctx, interrupted, checkpointOpts); !errors.Is(err, context.Canceled) {
+			t.Fatalf("interrupted run returned %v", err)
+		}
+		checkpoint, err := LoadCheckpoint(checkpointOpts.Checkpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(checkpoint.Coreset) >= len(want.Coreset) {
+			t.Fatalf("checkpoint holds %d of %d points", len(checkpoint.Coreset), len(want.Coreset))
+		}
+
+		resumeOpts := opts
+		resumeOpts.Resume = checkpoint
+		got, err := Solve(store, resumeOpts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sameSelection(t, got, want)
+	})
+}
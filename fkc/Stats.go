@@ -0,0 +1,100 @@
+package fkc
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+/**
+Bookkeeping for a single Solve call, shared by every algorithm and worker,
+along with the objective weights and costs they all evaluate gains with.
+*/
+
+type solverRun struct {
+	ctx       context.Context // Cancels every worker pool of the run
+	gainEvals int64           // Marginal gain evaluations, updated atomically
+	queries   int64           // Point store queries, updated atomically
+	phases    []Phase         // Completed phases, in order
+	weights   *gainWeights    // Objective weights, or nil if every node and group weighs 1
+	costs     []float64       // Cost of each point when gains are divided by cost, otherwise nil
+	target    float64         // Fraction of the initial objective to satisfy
+	stopScore float64         // Remaining score at which the target is met
+	rng       *rand.Rand      // Source of all randomness, seeded from Options.Seed
+	source    *countingSource // The source behind rng, counting its draws for checkpoints
+	tieBreak  TieBreak        // How candidates with equal priority are ordered
+	tieRanks  []float64       // Random rank of each point under RandomTie, otherwise nil
+}
+
+// Wall time spent in one phase of Solve
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+func newSolverRun(ctx context.Context, seed int64) *solverRun {
+	source := newCountingSource(seed)
+	return &solverRun{
+		ctx:    ctx,
+		phases: make([]Phase, 0),
+		rng:    rand.New(source),
+		source: source,
+	}
+}
+
+// What the algorithms maximize when picking a point: its marginal gain, or
+// its gain per unit of cost in cost-aware mode
+func (run *solverRun) priority(index int, gain float64) float64 {
+	if run.costs == nil {
+		return gain
+	}
+	return gain / run.costs[index]
+}
+
+func (run *solverRun) countGainEval() {
+	atomic.AddInt64(&run.gainEvals, 1)
+}
+
+// Records a phase that started at the given time and ends now
+func (run *solverRun) endPhase(name string, start time.Time) {
+	run.phases = append(run.phases, Phase{Name: name, Duration: time.Since(start)})
+}
+
+/**
+PointStore wrapper that counts every query issued to the underlying store.
+*/
+
+type countingStore struct {
+	store PointStore
+	run   *solverRun
+}
+
+func (s *countingStore) count() {
+	atomic.AddInt64(&s.run.queries, 1)
+}
+
+func (s *countingStore) Size() (int, error) {
+	s.count()
+	return s.store.Size()
+}
+
+func (s *countingStore) GetPoint(index int) (Point, error) {
+	s.count()
+	return s.store.GetPoint(index)
+}
+
+func (s *countingStore) FullIterator() (PointIterator, error) {
+	s.count()
+	return s.store.FullIterator()
+}
+
+func (s *countingStore) RangeIterator(lo int, hi int) (PointIterator, error) {
+	s.count()
+	return s.store.RangeIterator(lo, hi)
+}
+
+func (s *countingStore) SetIterator(set map[int]bool) (PointIterator, error) {
+	s.count()
+	return s.store.SetIterator(set)
+}
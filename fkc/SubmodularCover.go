@@ -0,0 +1,457 @@
+/*
+Package fkc solves the fair k-cover problem: select a small coreset of points
+such that every point has at least k neighbors in the coreset, and every group
+has a required number of points in the coreset.
+*/
+package fkc
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+/**
+Public API.
+*/
+
+// Which submodular cover algorithm Solve runs
+type Algorithm int
+
+const (
+	ClassicGreedy  Algorithm = iota // Classic greedy
+	LazyGreedy                      // Lazy greedy
+	LazyLazyGreedy                  // Lazy Lazy greedy
+	MultiLevel                      // Multilevel with lazylazy -> lazy
+	DisCover                        // Distributed submodular cover using GreeDi & lazygreedy as subroutines
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case ClassicGreedy:
+		return "classic greedy"
+	case LazyGreedy:
+		return "lazy greedy"
+	case LazyLazyGreedy:
+		return "lazylazy greedy"
+	case MultiLevel:
+		return "multilevel"
+	case DisCover:
+		return "DisCover"
+	default:
+		return "Algorithm(" + fmt.Sprint(int(a)) + ")"
+	}
+}
+
+type Result struct {
+	Coreset         []int        // Indices of the chosen points, in selection order
+	Gains           []float64    // Marginal gain of each coreset point when it was selected
+	CoverageTracker []int        // Residual coverage requirement of each point
+	GroupTracker    []int        // Residual requirement of each group
+	CapTracker      []int        // Remaining capacity of each group, negative if uncapped, or nil without caps
+	Satisfied       bool         // Whether every requirement was met
+	Fraction        float64      // Fraction of the initial objective that was satisfied
+	Objective       float64      // Reduction of the objective, the weighted requirement met by the coreset
+	TotalCost       float64      // Sum of the costs of the coreset points
+	Feasibility     *Feasibility // Requirements the data cannot meet; these were lowered if Options.Clip or a budget is set
+	GainEvals       int64        // Number of marginal gain evaluations
+	Queries         int64        // Number of point store queries issued by the solver
+	Pruned          int          // Number of redundant points removed by Options.Prune
+	Phases          []Phase      // Wall time of each phase, in order
+}
+
+// Runs the chosen algorithm on the points in store. If the point store fails
+// midway, the result describes the coreset built so far and is returned
+// along with the error.
+func Solve(store PointStore, opts Options) (*Result, error) {
+	return SolveContext(context.Background(), store, opts)
+}
+
+// Same as Solve, but stops early once ctx is done. The result then describes
+// the coreset built so far, with the residual trackers, and is returned along
+// with ctx's error.
+func SolveContext(ctx context.Context, store PointStore, opts Options) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Previous != nil { // The previous coreset stays selected
+		opts.Initial = opts.Previous.Coreset
+	}
+	if opts.Budget >= 0 { // Maximizing within a budget needs no full cover
+		opts.Clip = true
+	}
+	run := newSolverRun(ctx, opts.Seed)
+	bound := store
+	if cs, ok := store.(ContextStore); ok {
+		bound = cs.WithContext(ctx)
+	}
+	counted := &countingStore{store: bound, run: run}
+
+	// Check feasibility & initialize trackers
+	start := time.Now()
+	n, err := counted.Size()
+	if err != nil {
+		return nil, err
+	}
+	scan, err := scanPoints(counted, n, opts)
+	if err != nil {
+		return nil, err
+	}
+	feasibility := checkFeasibility(scan, opts)
+	if !opts.Clip && !feasibility.Feasible() {
+		return nil, &InfeasibleError{Feasibility: feasibility}
+	}
+	coverageTracker, groupTracker, capTracker := initTrackers(scan, opts)
+	run.weights, err = newGainWeights(opts, n, len(groupTracker))
+	if err != nil {
+		return nil, err
+	}
+	if opts.CostAware {
+		run.costs = scan.costs
+	}
+	run.tieBreak = opts.TieBreak
+	if opts.TieBreak == RandomTie {
+		run.tieRanks = newTieRanks(n, opts.Seed)
+	}
+	initialScore := remainingScore(run, coverageTracker, groupTracker)
+	run.target = opts.Target
+	run.stopScore = (1 - opts.Target) * initialScore
+	initialCoverage := make([]int, n) // Trackers are consumed in place
+	initialGroups := make([]int, len(groupTracker))
+	copy(initialCoverage, coverageTracker)
+	copy(initialGroups, groupTracker)
+	initialCaps := append([]int(nil), capTracker...)
+	var initialGains []float64
+	if opts.Previous != nil {
+		initialGains, err = extendTrackers(run, counted, opts.Previous, coverageTracker, groupTracker, capTracker)
+	} else {
+		initialGains, err = warmStart(run, counted, opts.Initial, coverageTracker, groupTracker, capTracker)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resume := opts.Resume; resume != nil { // Pick up the checkpointed state
+		if err := resume.check(opts, coverageTracker, groupTracker, capTracker); err != nil {
+			return nil, err
+		}
+		copy(coverageTracker, resume.CoverageTracker)
+		copy(groupTracker, resume.GroupTracker)
+		copy(capTracker, resume.CapTracker)
+		run.source.skip(resume.RandomDraws)
+		run.gainEvals += resume.GainEvals
+		run.queries += resume.Queries
+	}
+	run.endPhase("initialize trackers", start)
+	report("initialized trackers\n", opts.Print)
+
+	// Choose algorithm to run
+	cp := newCheckpointer(run, opts)
+	coreset, gains, solveErr := runAlgorithm(run, counted, coverageTracker, groupTracker, capTracker, n, opts, cp)
+	coreset = append(append([]int(nil), opts.Initial...), coreset...)
+	gains = append(initialGains, gains...)
+
+	var result *Result
+	if solveErr != nil {
+		// The algorithm may have stopped between updating its coreset & the
+		// trackers, so replay the selection, initial points first, to recover
+		// each step's gain & the final residuals. This uses the unbound
+		// store, so that it still works once ctx is done.
+		start = time.Now()
+		replayStore := &countingStore{store: store, run: run}
+		result, err = replayCoreset(run, replayStore, coreset, initialCoverage, initialGroups, initialCaps)
+		run.endPhase("replay", start)
+	} else {
+		result = &Result{
+			Coreset:         coreset,
+			Gains:           gains,
+			CoverageTracker: coverageTracker,
+			GroupTracker:    groupTracker,
+			CapTracker:      capTracker,
+			Satisfied:       !notSatisfied(coverageTracker, groupTracker),
+		}
+		if opts.Prune { // Drop redundant points
+			start = time.Now()
+			result, solveErr = pruneCoreset(run, counted, result, len(opts.Initial), initialCoverage, initialGroups, initialCaps)
+			run.endPhase("prune", start)
+		}
+	}
+	start = time.Now()
+	result.Feasibility = feasibility
+	result.Objective = initialScore - remainingScore(run, result.CoverageTracker, result.GroupTracker)
+	result.Fraction = 1
+	if initialScore > 0 {
+		result.Fraction = result.Objective / initialScore
+	}
+	for _, index := range result.Coreset {
+		result.TotalCost += scan.costs[index]
+	}
+	run.endPhase("report", start)
+	result.GainEvals = run.gainEvals
+	result.Queries = run.queries
+	result.Phases = run.phases
+	if solveErr != nil {
+		return result, solveErr
+	}
+	return result, err
+}
+
+func runAlgorithm(run *solverRun, store PointStore, coverageTracker []int, groupTracker []int,
+	capTracker []int, n int, opts Options, cp *checkpointer) ([]int, []float64, error) {
+	threads, print := opts.Threads, opts.Print
+	pool := setMinus(rangeSet(n), sliceToSet(opts.Initial)) // Initial points are already selected
+	start := time.Now()
+	switch opts.Algorithm {
+	case ClassicGreedy:
+		defer run.endPhase(ClassicGreedy.String(), start)
+		return classicGreedy(run, store, coverageTracker, groupTracker, capTracker, pool, opts.Budget, threads, print, cp)
+	case LazyGreedy:
+		defer run.endPhase(LazyGreedy.String(), start)
+		return lazyGreedy(run, store, coverageTracker, groupTracker, capTracker, pool, opts.Budget, threads, print, cp)
+	case LazyLazyGreedy:
+		defer run.endPhase(LazyLazyGreedy.String(), start)
+		return lazyLazyGreedy(run, store, coverageTracker, groupTracker, capTracker, pool, opts.Budget, threads, print, opts.Eps, 1.0, cp)
+	case MultiLevel: // Each stage is its own phase
+		var firstStage []int
+		var firstGains []float64
+		if resume := opts.Resume; resume != nil && resume.Stage > 0 { // The first stage had finished
+			firstStage = append([]int(nil), resume.Coreset[:resume.StageStart]...)
+			firstGains = append([]float64(nil), resume.Gains[:resume.StageStart]...)
+		} else {
+			var err error
+			firstStage, firstGains, err = lazyLazyGreedy(run, store, coverageTracker, groupTracker, capTracker, pool, opts.Budget, threads, print, opts.Eps, opts.ObjRatio, cp)
+			run.endPhase(LazyLazyGreedy.String(), start)
+			if err != nil {
+				return firstStage, firstGains, err
+			}
+		}
+		start = time.Now()
+		cp.nextStage(firstStage, firstGains)
+		candidates := setMinus(pool, sliceToSet(firstStage))
+		budget := opts.Budget
+		if budget >= 0 { // The second stage gets whatever the first left over
+			budget = max(0, budget-len(firstStage))
+		}
+		secondStage, secondGains, err := lazyGreedy(run, store, coverageTracker, groupTracker, capTracker, candidates, budget, threads, print, cp)
+		run.endPhase(LazyGreedy.String(), start)
+		return append(firstStage, secondStage...), append(firstGains, secondGains...), err
+	case DisCover:
+		defer run.endPhase(DisCover.String(), start)
+		return disCover(run, store, coverageTracker, groupTracker, capTracker, pool, opts.Budget, threads, opts.Alpha, print, cp)
+	default:
+		return nil, nil, fmt.Errorf("unknown algorithm %v", opts.Algorithm)
+	}
+}
+
+// Selects the initial points up front, as if an algorithm had chosen them, and
+// returns their marginal gains in that order. Fails if they exceed a group cap.
+func warmStart(run *solverRun, store PointStore, initial []int, coverageTracker []int, groupTracker []int,
+	capTracker []int) ([]float64, error) {
+	gains := make([]float64, 0, len(initial))
+	for _, index := range initial {
+		if index >= len(coverageTracker) {
+			return nil, fmt.Errorf("initial point %d out of range, store has %d points", index, len(coverageTracker))
+		}
+		point, err := store.GetPoint(index)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range point.Groups {
+			if capTracker != nil && capTracker[group] == 0 {
+				return nil, fmt.Errorf("initial point %d exceeds the cap of group %d", index, group)
+			}
+		}
+		gains = append(gains, weightedGain(run.weights, point, coverageTracker, groupTracker, 1))
+		decrementTrackers(&point, coverageTracker, groupTracker, capTracker)
+	}
+	return gains, nil
+}
+
+// Applies the coreset to fresh copies of the initial trackers in selection
+// order. Each step's marginal gain is identical to the one the algorithm saw
+// when it picked the point, since trackers only change through selections.
+func replayCoreset(run *solverRun, store PointStore, coreset []int,
+	coverageTracker []int, groupTracker []int, capTracker []int) (*Result, error) {
+	result := &Result{
+		Coreset:         coreset,
+		Gains:           make([]float64, 0, len(coreset)),
+		CoverageTracker: coverageTracker,
+		GroupTracker:    groupTracker,
+		CapTracker:      capTracker,
+	}
+	if result.Coreset == nil {
+		result.Coreset = []int{}
+	}
+	for _, index := range coreset {
+		point, err := store.GetPoint(index)
+		if err != nil {
+			return result, err
+		}
+		gain := weightedGain(run.weights, point, coverageTracker, groupTracker, 1) // Already counted when selected
+		result.Gains = append(result.Gains, gain)
+		decrementTrackers(&point, coverageTracker, groupTracker, capTracker)
+	}
+	result.Satisfied = !notSatisfied(coverageTracker, groupTracker)
+	return result, nil
+}
+
+func marginalGain(run *solverRun, point Point, coverageTracker []int, groupTracker []int, threads int) float64 {
+	run.countGainEval()
+	return weightedGain(run.weights, point, coverageTracker, groupTracker, threads)
+}
+
+func weightedGain(weights *gainWeights, point Point, coverageTracker []int, groupTracker []int, threads int) float64 {
+	nodeWeights := weights.nodeWeights()
+	gain := 0.0
+	if threads <= 1 { // Singlethreaded
+		if point.NeighborBits != nil { // Marginal gain from k-Coverage
+			gain = bitsGainWorker(point.NeighborBits, coverageTracker, nodeWeights, 0)
+		} else {
+			gain = gainWorker(point.Neighbors, coverageTracker, nodeWeights)
+		}
+	} else { // Multithreaded
+		// Split whichever representation is used into chunks
+		var length int
+		if point.NeighborBits != nil {
+			length = len(point.NeighborBits)
+		} else {
+			length = len(point.Neighbors)
+		}
+		chunkSize := length/threads + 1
+		starts := make([]int, threads)
+		for t := 0; t < threads; t++ {
+			starts[t] = min(length, t*chunkSize)
+		}
+		// Call workers; gain workers cannot fail
+		results, _ := parallelMap(context.Background(), threads, starts,
+			func(_ context.Context, lo int) (float64, error) {
+				hi := min(length, lo+chunkSize)
+				if point.NeighborBits != nil {
+					return bitsGainWorker(point.NeighborBits[lo:hi], coverageTracker, nodeWeights, lo*64), nil
+				}
+				return gainWorker(point.Neighbors[lo:hi], coverageTracker, nodeWeights), nil
+			})
+		// Total up results
+		for _, sum := range results {
+			gain += sum
+		}
+	}
+	for _, group := range point.Groups { // Marginal gain from group requirements
+		gain += weights.groupGain(group, groupTracker)
+	}
+	return gain
+}
+
+// Sums the residual coverage of the neighbors, weighted by nodeWeights unless
+// it is nil
+func gainWorker(neighbors []int, coverageTracker []int, nodeWeights []float64) float64 {
+	if nodeWeights == nil {
+		sum := 0
+		for _, neighbor := range neighbors {
+			sum += coverageTracker[neighbor]
+		}
+		return float64(sum)
+	}
+	sum := 0.0
+	for _, neighbor := range neighbors {
+		sum += nodeWeights[neighbor] * float64(coverageTracker[neighbor])
+	}
+	return sum
+}
+
+// Same as gainWorker, but over bitset words whose first bit is node offset.
+// Only set bits are visited, so zero words cost a single comparison.
+func bitsGainWorker(words Bitset, coverageTracker []int, nodeWeights []float64, offset int) float64 {
+	sum := 0
+	weighted := 0.0
+	for w, word := range words {
+		base := offset + w*64
+		for word != 0 {
+			neighbor := base + bits.TrailingZeros64(word)
+			if nodeWeights == nil {
+				sum += coverageTracker[neighbor]
+			} else {
+				weighted += nodeWeights[neighbor] * float64(coverageTracker[neighbor])
+			}
+			word &= word - 1
+		}
+	}
+	return float64(sum) + weighted
+}
+
+func getMarginalGains(ctx context.Context, run *solverRun, store PointStore, coverageTracker []int,
+	groupTracker []int, capTracker []int, candidates map[int]bool) ([]*pqItem, error) {
+	// Query the database
+	it, err := store.SetIterator(candidates)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	// Get results by iterating the cursor
+	results := make([]*pqItem, 0)
+	for it.Next() {
+		if err := ctx.Err(); err != nil { // Another worker failed
+			return results, err
+		}
+		point, err := it.Point()
+		if err != nil {
+			return results, err
+		}
+		if saturated(&point, capTracker) { // Can never be selected
+			continue
+		}
+		gain := marginalGain(run, point, coverageTracker, groupTracker, 1)
+		item := &pqItem{
+			value:    point.Index,
+			priority: run.priority(point.Index, gain),
+			tieKey:   run.tieKey(&point, groupTracker),
+		}
+		results = append(results, item)
+	}
+	return results, it.Err()
+}
+
+func notSatisfied(coverageTracker []int, groupTracker []int) bool {
+	return sum(coverageTracker)+sum(groupTracker) > 0
+}
+
+// Whether enough of the objective is satisfied to stop. A full cover is
+// checked exactly, since points with zero weight don't show in the score.
+func (run *solverRun) targetMet(coverageTracker []int, groupTracker []int) bool {
+	if run.target >= 1 {
+		return !notSatisfied(coverageTracker, groupTracker)
+	}
+	return remainingScore(run, coverageTracker, groupTracker) <= run.stopScore
+}
+
+func decrementTrackers(point *Point, coverageTracker []int, groupTracker []int, capTracker []int) {
+	point.forEachNeighbor(func(neighbor int) {
+		coverageTracker[neighbor] = max(0, coverageTracker[neighbor]-1)
+	})
+	for _, group := range point.Groups {
+		groupTracker[group] = max(0, groupTracker[group]-1)
+		if capTracker != nil && capTracker[group] > 0 {
+			capTracker[group]--
+		}
+	}
+}
+
+// Whether one of the point's groups has no capacity left, so that selecting
+// the point would exceed that group's cap
+func saturated(point *Point, capTracker []int) bool {
+	if capTracker == nil {
+		return false
+	}
+	for _, group := range point.Groups {
+		if capTracker[group] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Weighted sum of residual requirements, which the algorithms drive to zero
+func remainingScore(run *solverRun, coverageTracker []int, groupTracker []int) float64 {
+	return run.weights.score(coverageTracker, groupTracker)
+}
@@ -0,0 +1,90 @@
+package fkc
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+/**
+Tie-breaking between candidates with the same marginal gain. Every policy
+computes a tie key for a point, the higher key wins, and remaining ties go to
+the lower index so that the choice never depends on iteration order.
+*/
+
+type TieBreak int
+
+const (
+	LowestIndex      TieBreak = iota // Lowest index
+	HighestDegree                    // Most neighbors
+	RandomTie                        // Random order fixed by Options.Seed
+	Underrepresented                 // Group furthest from its requirement
+)
+
+func (tb TieBreak) String() string {
+	switch tb {
+	case LowestIndex:
+		return "index"
+	case HighestDegree:
+		return "degree"
+	case RandomTie:
+		return "random"
+	case Underrepresented:
+		return "group"
+	default:
+		return "TieBreak(" + fmt.Sprint(int(tb)) + ")"
+	}
+}
+
+// Parses the name String returns
+func ParseTieBreak(name string) (TieBreak, error) {
+	for tb := LowestIndex; tb <= Underrepresented; tb++ {
+		if tb.String() == name {
+			return tb, nil
+		}
+	}
+	return LowestIndex, fmt.Errorf("unknown tie-break policy %q, expected index, degree, random or group", name)
+}
+
+// A random rank for each point, drawn from its own source so that the
+// sampling sequence of LazyLazy is the same under every policy
+func newTieRanks(n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	ranks := make([]float64, n)
+	for i := range ranks {
+		ranks[i] = rng.Float64()
+	}
+	return ranks
+}
+
+// The tie key of a point under the run's policy. The group policy looks at
+// the current residuals, so keys stored in the priority queue go stale the
+// same way its gains do.
+func (run *solverRun) tieKey(point *Point, groupTracker []int) float64 {
+	switch run.tieBreak {
+	case HighestDegree:
+		return float64(point.degree())
+	case RandomTie:
+		return run.tieRanks[point.Index]
+	case Underrepresented:
+		furthest := 0
+		for _, group := range point.Groups {
+			furthest = max(furthest, groupTracker[group])
+		}
+		return float64(furthest)
+	default:
+		return 0
+	}
+}
+
+// Whether the first candidate ranks above the second: higher priority, then
+// higher tie key, then lower index
+func outranks(priority float64, tieKey float64, index int,
+	otherPriority float64, otherTieKey float64, otherIndex int) bool {
+	if priority != otherPriority {
+		return priority > otherPriority
+	}
+	if tieKey != otherTieKey {
+		return tieKey > otherTieKey
+	}
+	return index < otherIndex
+}
@@ -0,0 +1,73 @@
+package fkc
+
+import (
+	"fmt"
+)
+
+/**
+Weights of the fair k-cover objective. The objective is the weighted sum of
+residual requirements, so a point's marginal gain is the weighted residual
+coverage of its neighbors plus the weighted residual requirement of its group.
+*/
+
+type gainWeights struct {
+	node  []float64 // Weight of each point's residual coverage, or nil if all are 1
+	group []float64 // Weight of each group's residual requirement, or nil if all are 1
+}
+
+// Returns nil when every weight is 1, so that Solve keeps to integer sums
+func newGainWeights(opts Options, n int, numGroups int) (*gainWeights, error) {
+	if len(opts.NodeWeights) == 0 && len(opts.GroupWeights) == 0 {
+		return nil, nil
+	}
+	if len(opts.GroupWeights) != 0 && len(opts.GroupWeights) != numGroups {
+		return nil, fmt.Errorf("invalid options: %d group weights given but the data has %d groups",
+			len(opts.GroupWeights), numGroups)
+	}
+	weights := &gainWeights{}
+	if len(opts.NodeWeights) != 0 {
+		weights.node = make([]float64, n)
+		for i := range weights.node {
+			weights.node[i] = 1
+		}
+		for index, weight := range opts.NodeWeights {
+			if index >= 0 && index < n { // Points missing from the data are ignored
+				weights.node[index] = weight
+			}
+		}
+	}
+	if len(opts.GroupWeights) != 0 {
+		weights.group = opts.GroupWeights
+	}
+	return weights, nil
+}
+
+func (w *gainWeights) nodeWeights() []float64 {
+	if w == nil {
+		return nil
+	}
+	return w.node
+}
+
+func (w *gainWeights) groupGain(group int, groupTracker []int) float64 {
+	if w == nil || w.group == nil {
+		return float64(groupTracker[group])
+	}
+	return w.group[group] * float64(groupTracker[group])
+}
+
+// Weighted sum of all residual requirements
+func (w *gainWeights) score(coverageTracker []int, groupTracker []int) float64 {
+	score := 0.0
+	if node := w.nodeWeights(); node != nil {
+		for i, residual := range coverageTracker {
+			score += node[i] * float64(residual)
+		}
+	} else {
+		score = float64(sum(coverageTracker))
+	}
+	for group := range groupTracker {
+		score += w.groupGain(group, groupTracker)
+	}
+	return score
+}
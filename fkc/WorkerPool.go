@@ -0,0 +1,63 @@
+package fkc
+
+import (
+	"context"
+	"sync"
+)
+
+/**
+Typed worker pool that every algorithm fans its work out through.
+*/
+
+// Calls f on every input using at most limit goroutines and returns the
+// outputs in input order, whichever call finishes first. The first error
+// cancels the context handed to the other calls and skips the inputs that
+// haven't started yet; it is returned along with the outputs produced so far.
+func parallelMap[In any, Out any](ctx context.Context, limit int, inputs []In,
+	f func(context.Context, In) (Out, error)) ([]Out, error) {
+	poolCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	outputs := make([]Out, len(inputs))
+	var once sync.Once
+	var firstErr error
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// Start the workers, each pulling the position of its next input
+	next := make(chan int)
+	var wg sync.WaitGroup
+	workers := min(max(1, limit), len(inputs))
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				output, err := f(poolCtx, inputs[i])
+				outputs[i] = output
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	// Hand out inputs until they run out or the pool is cancelled
+feed:
+	for i := range inputs {
+		select {
+		case next <- i:
+		case <-poolCtx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	if firstErr != nil {
+		return outputs, firstErr
+	}
+	return outputs, ctx.Err()
+}
@@ -0,0 +1,292 @@
+package fkc
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/**
+Utility related to reasoning about the result of a marginal gain evaluation
+*/
+
+type gainResult struct {
+	index    int
+	gain     float64
+	priority float64 // Gain, or gain per unit of cost in cost-aware mode
+	tieKey   float64 // Decides between equal priorities, see TieBreak
+}
+
+func setEmptyResult() *gainResult {
+	return &gainResult{
+		index:    -1,
+		gain:     -1,
+		priority: -1,
+	}
+}
+
+// Whether a result beats the result so far. Ties are broken by the tie key
+// and then the index, so that the outcome doesn't depend on the order in which
+// points are iterated or workers finish.
+func preferred(res *gainResult, than *gainResult) bool {
+	return outranks(res.priority, res.tieKey, res.index, than.priority, than.tieKey, than.index)
+}
+
+func getBestResult(results []*gainResult) *gainResult {
+	best := setEmptyResult()
+	for _, res := range results {
+		if res != nil && preferred(res, best) {
+			best = res
+		}
+	}
+	return best
+}
+
+/**
+The type of a database entry.
+*/
+
+type Point struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Index        int                `bson:"index"`
+	Groups       GroupSet           `bson:"group"`
+	Coverage     *int               `bson:"coverage,omitempty"` // Coverage requirement overriding the uniform one
+	Cost         *float64           `bson:"cost,omitempty"`     // Selection cost, 1 if absent
+	Neighbors    NeighborList       `bson:"neighbors,omitempty"`
+	NeighborBits Bitset             `bson:"neighborbits,omitempty"` // Set instead of Neighbors for bitset graphs
+}
+
+/**
+Miscellaneous functions.
+*/
+
+func removeFromSlice(s []int, index int) []int {
+	for i := 0; i < len(s); i++ {
+		if i == index {
+			s[i] = s[len(s)-1]
+			return s[:len(s)-1]
+		}
+	}
+	return s
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func sum(slice []int) int {
+	sum := 0
+	for i := range slice {
+		sum += slice[i]
+	}
+	return sum
+}
+
+// Whether both slices hold the same values in the same order; nil & empty are equal
+func equalSlices[T int | float64](a []T, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether both maps hold the same entries; nil & empty are equal
+func equalMaps[T int | float64](a map[int]T, b map[int]T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func rangeSlice(n int) []int {
+	result := make([]int, n)
+	for i := 0; i < n; i++ {
+		result[i] = i
+	}
+	return result
+}
+
+func rangeSet(n int) map[int]bool {
+	result := make(map[int]bool, n)
+	for i := 0; i < n; i++ {
+		result[i] = true
+	}
+	return result
+}
+
+func deleteAllFromSet(set map[int]bool, keys []int) map[int]bool {
+	for i := 0; i < len(keys); i++ {
+		key := keys[i]
+		delete(set, key)
+	}
+	return set
+}
+
+func report(message string, print bool) {
+	if print {
+		fmt.Printf(message)
+	}
+}
+
+// Keys of the set in increasing order, so that iterating over them is
+// reproducible unlike iterating over the map itself
+func mapToSlice(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Splits the set into contiguous runs of its sorted keys
+func splitSet(set map[int]bool, threads int) []map[int]bool {
+	result := make([]map[int]bool, threads)
+	countPerSplit := (len(set) / threads) + 1
+	for i := 0; i < threads; i++ {
+		result[i] = make(map[int]bool, 0)
+	}
+	for i, key := range mapToSlice(set) {
+		assign := i / countPerSplit
+		result[assign][key] = true
+	}
+	return result
+}
+
+func subSampleSet(set map[int]bool, size int, rng *rand.Rand) map[int]bool {
+	result := make(map[int]bool, size)
+	for i, item := range mapToSlice(set) {
+		prob := float64(size-len(result)) / float64(len(set)-i)
+		if rng.Float64() <= prob { // Success
+			result[item] = true
+		}
+	}
+	return result
+}
+
+func notSatisfiedIndices(coverageTracker []int) []int {
+	result := make([]int, 0)
+	for i := 0; i < len(coverageTracker); i++ {
+		if coverageTracker[i] > 0 {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func sliceToSet(slice []int) map[int]bool {
+	result := make(map[int]bool, len(slice))
+	for i := 0; i < len(slice); i++ {
+		result[slice[i]] = true
+	}
+	return result
+}
+
+func setMinus(foo map[int]bool, bar map[int]bool) map[int]bool {
+	result := make(map[int]bool)
+	for key, _ := range foo {
+		if !bar[key] {
+			result[key] = true
+		}
+	}
+	return result
+}
+
+/**
+Everything required to implement priority queue.
+*/
+
+type pqItem struct {
+	value    int
+	priority float64
+	tieKey   float64
+	index    int
+}
+
+func getEmptyItem() *pqItem {
+	return &pqItem{
+		value:    -1,
+		priority: -1,
+		index:    0,
+	}
+}
+
+type priorityQueue []*pqItem
+
+func (pq priorityQueue) Len() int { return len(pq) }
+
+func (pq priorityQueue) Less(i, j int) bool {
+	// We want Pop to give us the highest, not lowest, priority so we use greater than here.
+	// Ties are broken the same way as everywhere else, so that pops don't
+	// depend on insertion order.
+	return outranks(pq[i].priority, pq[i].tieKey, pq[i].value, pq[j].priority, pq[j].tieKey, pq[j].value)
+}
+
+func (pq priorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *priorityQueue) Push(x any) {
+	n := len(*pq)
+	item := x.(*pqItem)
+	item.index = n
+	*pq = append(*pq, item)
+}
+
+func (pq *priorityQueue) Pop() any {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil  // avoid memory leak
+	item.index = -1 // for safety
+	*pq = old[0 : n-1]
+	return item
+}
+
+// update modifies the priority and value of a pqItem in the queue.
+func (pq *priorityQueue) update(item *pqItem, value int, priority float64) {
+	item.value = value
+	item.priority = priority
+	heap.Fix(pq, item.index)
+}
+
+func peekPriority(pq *priorityQueue) float64 {
+	//return (*pq)[len(*pq)-1].priority
+	return (*pq)[0].priority
+}
+
+// Whether the head of the queue ranks above the given candidate
+func (pq priorityQueue) topOutranks(priority float64, tieKey float64, value int) bool {
+	top := pq[0]
+	return outranks(top.priority, top.tieKey, top.value, priority, tieKey, value)
+}
+
+func removeFromPQ(pq *priorityQueue, pos int) {
+	pq.Swap(pos, len(*pq)-1)
+	pq.Pop()
+}